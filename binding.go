@@ -0,0 +1,501 @@
+package swag
+
+import (
+	"fmt"
+	"go/ast"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/go-openapi/spec"
+)
+
+// bindingGenFile declares the input structs and adapters of the typed handlers in a package.
+const bindingGenFile = "binding_gen.go"
+
+// ParamGoType is the Go type named by a @Param, e.g. []model.Account for a body parameter.
+type ParamGoType struct {
+	// ImportPath is the import path of the package declaring Type, empty for the package of the handler
+	ImportPath string
+
+	// Type is the name of the type
+	Type string
+
+	// Array whether the parameter is a slice of Type
+	Array bool
+}
+
+// parseParamGoType resolves the type of a @Param against the imports of astFile, nil for generic types.
+func parseParamGoType(typ string, astFile *ast.File) *ParamGoType {
+	goType := &ParamGoType{Type: typ}
+	if strings.HasPrefix(goType.Type, "[]") {
+		goType.Array = true
+		goType.Type = strings.TrimPrefix(goType.Type, "[]")
+	}
+
+	if strings.ContainsAny(goType.Type, "[]{}| ") {
+		return nil
+	}
+
+	if i := strings.LastIndex(goType.Type, "."); i >= 0 && astFile != nil {
+		importPath, ok := findImportPath(astFile, goType.Type[:i])
+		if !ok {
+			return nil
+		}
+
+		goType.ImportPath = importPath
+		goType.Type = goType.Type[i+1:]
+	}
+
+	return goType
+}
+
+// typedHandler is a typed handler the adapter in binding_gen.go calls.
+type typedHandler struct {
+	name      string // function or method name
+	owner     HandlerReceiver
+	input     string
+	params    []spec.Parameter
+	bodyType  *ParamGoType
+	success   int
+	failures  []int
+	handlerAt string
+}
+
+// adapterName returns the name of the gin adapter of the typed handler name.
+func adapterName(name string) string {
+	return name + "Handler"
+}
+
+// genBindingFiles generates binding_gen.go next to the typed handlers, declaring their input structs
+// built from @Param and the gin adapters binding and validating the input before calling them.
+func genBindingFiles(routes map[Routes][]RouteInfos, config GenConfig) error {
+	type bindingPkg struct {
+		path     string
+		name     string
+		handlers map[string]*typedHandler
+	}
+
+	pkgs := make(map[string]*bindingPkg)
+	for _, infos := range routes {
+		for _, info := range infos {
+			if info.signature.Kind != HandlerKindTyped {
+				continue
+			}
+
+			pkg, ok := pkgs[info.handlerDir]
+			if !ok {
+				pkg = &bindingPkg{
+					path:     info.handlerPkg,
+					name:     info.HandlerFun[:strings.Index(info.HandlerFun, ".")],
+					handlers: make(map[string]*typedHandler),
+				}
+				pkgs[info.handlerDir] = pkg
+			}
+
+			name := info.HandlerFun[strings.LastIndex(info.HandlerFun, ".")+1:]
+			if info.signature.Owner.Type != "" {
+				name = info.signature.Owner.Type + "." + name
+			}
+
+			if _, ok := pkg.handlers[name]; ok {
+				continue
+			}
+
+			handler := &typedHandler{
+				name:      name[strings.LastIndex(name, ".")+1:],
+				owner:     info.signature.Owner,
+				input:     info.signature.Input,
+				params:    info.operation.Parameters,
+				bodyType:  info.bodyType,
+				success:   http.StatusOK,
+				handlerAt: info.signature.Pos,
+			}
+
+			successFound := false
+			for _, code := range info.Responses {
+				if code >= 200 && code < 300 && !successFound {
+					handler.success, successFound = code, true
+				} else if code >= 400 {
+					handler.failures = append(handler.failures, code)
+				}
+			}
+
+			pkg.handlers[name] = handler
+		}
+	}
+
+	var dirs []string
+	for dir := range pkgs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		pkg := pkgs[dir]
+
+		var names []string
+		for name := range pkg.handlers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		f := jen.NewFilePathName(pkg.path, pkg.name)
+		f.ImportName(ginImportPath, "gin")
+		inputs := make(map[string]*typedHandler)
+		for _, name := range names {
+			handler := pkg.handlers[name]
+			fields, err := bindingFields(handler)
+			if err != nil {
+				return fmt.Errorf("%s: %s", handler.handlerAt, err)
+			}
+
+			// the input struct is built from the @Param comments of a single handler
+			if other, ok := inputs[handler.input]; ok {
+				return fmt.Errorf("%s: %s is already the input of %s at %s, declare an input type per handler",
+					handler.handlerAt, handler.input, other.name, other.handlerAt)
+			}
+
+			inputs[handler.input] = handler
+			f.Commentf("%s is the input of %s bound from its @Param comments.", handler.input, handler.name)
+			f.Type().Id(handler.input).StructFunc(func(g *jen.Group) {
+				for _, field := range fields {
+					g.Id(field.name).Add(field.typ).Tag(field.tags)
+				}
+			})
+			f.Line()
+
+			genAdapter(f, handler, fields)
+			f.Line()
+		}
+
+		err := renderGenFile(f, filepath.Join(dir, bindingGenFile), config)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindingField is a field of an input struct.
+type bindingField struct {
+	name  string
+	in    string
+	typ   jen.Code
+	tags  map[string]string
+	param spec.Parameter
+}
+
+// bindingFields returns the input struct fields of the parameters of handler.
+func bindingFields(handler *typedHandler) ([]bindingField, error) {
+	var fields []bindingField
+
+	names := make(map[string]bool)
+	for _, param := range handler.params {
+		// gin binds no cookies, the handler reads them with c.Cookie
+		if param.In == "cookie" {
+			continue
+		}
+
+		field := bindingField{in: param.In, param: param, tags: make(map[string]string)}
+
+		field.name = exportedIdent(param.Name)
+		if param.In == "body" {
+			field.name = "Body"
+		}
+
+		if field.name == "" {
+			return nil, fmt.Errorf("parameter %s can not be turned into a field of %s", param.Name, handler.input)
+		}
+
+		if names[field.name] {
+			field.name += exportedIdent(param.In)
+		}
+
+		names[field.name] = true
+
+		switch param.In {
+		case "path":
+			field.tags["uri"] = param.Name
+		case "query", "formData":
+			field.tags["form"] = param.Name
+			if param.Default != nil && param.Type != ARRAY {
+				field.tags["form"] += ",default=" + fmt.Sprint(param.Default)
+			}
+		case "header":
+			field.tags["header"] = param.Name
+		case "body":
+			field.tags["json"] = "-"
+		}
+
+		if param.In == "body" {
+			field.typ = bodyGoType(handler.bodyType, param.Schema)
+		} else {
+			field.typ = paramGoType(param.Type, param.Format, param.Items)
+		}
+
+		if validation := bindingValidation(param); validation != "" {
+			field.tags["binding"] = validation
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// paramGoType maps a simple parameter type to a Go type.
+func paramGoType(typ, format string, items *spec.Items) *jen.Statement {
+	switch typ {
+	case INTEGER:
+		switch format {
+		case "int64":
+			return jen.Int64()
+		case "int32":
+			return jen.Int32()
+		}
+
+		return jen.Int()
+	case NUMBER:
+		if format == "float" {
+			return jen.Float32()
+		}
+
+		return jen.Float64()
+	case BOOLEAN:
+		return jen.Bool()
+	case "file":
+		return jen.Op("*").Qual("mime/multipart", "FileHeader")
+	case ARRAY:
+		if items == nil {
+			return jen.Index().String()
+		}
+
+		return jen.Index().Add(paramGoType(items.Type, items.Format, items.Items))
+	}
+
+	return jen.String()
+}
+
+// bodyGoType returns the Go type of a body parameter, json.RawMessage when it is unknown.
+func bodyGoType(goType *ParamGoType, schema *spec.Schema) jen.Code {
+	if goType == nil {
+		return jen.Qual("encoding/json", "RawMessage")
+	}
+
+	var typ *jen.Statement
+	if IsGolangPrimitiveType(goType.Type) || goType.Type == "any" {
+		typ = jen.Id(goType.Type)
+	} else if schema != nil && len(schema.Type) == 1 && IsSimplePrimitiveType(schema.Type[0]) && goType.ImportPath == "" {
+		typ = paramGoType(schema.Type[0], schema.Format, nil)
+	} else if goType.ImportPath == "" {
+		// binding_gen.go is generated in the package of the handler
+		typ = jen.Id(goType.Type)
+	} else {
+		typ = jen.Qual(goType.ImportPath, goType.Type)
+	}
+
+	if goType.Array {
+		return jen.Index().Add(typ)
+	}
+
+	return typ
+}
+
+// bindingValidation returns the gin binding tag validating the constraints documented for param.
+func bindingValidation(param spec.Parameter) string {
+	var rules []string
+
+	typ := param.Type
+	if param.In == "body" && param.Schema != nil && len(param.Schema.Type) == 1 {
+		typ = param.Schema.Type[0]
+	}
+
+	// gin's required rejects the zero value, a required false or 0 is still a value
+	if param.Required && typ != BOOLEAN && typ != INTEGER && typ != NUMBER {
+		rules = append(rules, "required")
+	}
+
+	if param.In == "body" {
+		return strings.Join(rules, ",")
+	}
+
+	var constraints []string
+	switch param.Type {
+	case STRING, ARRAY:
+		length := func(v *int64) string { return strconv.FormatInt(*v, 10) }
+		minLength, maxLength := param.MinLength, param.MaxLength
+		if param.Type == ARRAY {
+			minLength, maxLength = param.MinItems, param.MaxItems
+		}
+
+		if minLength != nil {
+			constraints = append(constraints, "min="+length(minLength))
+		}
+
+		if maxLength != nil {
+			constraints = append(constraints, "max="+length(maxLength))
+		}
+	case INTEGER, NUMBER:
+		number := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+		if param.Minimum != nil {
+			rule := "gte="
+			if param.ExclusiveMinimum {
+				rule = "gt="
+			}
+
+			constraints = append(constraints, rule+number(*param.Minimum))
+		}
+
+		if param.Maximum != nil {
+			rule := "lte="
+			if param.ExclusiveMaximum {
+				rule = "lt="
+			}
+
+			constraints = append(constraints, rule+number(*param.Maximum))
+		}
+	}
+
+	if len(param.Enum) > 0 && param.Type != ARRAY {
+		var values []string
+		for _, value := range param.Enum {
+			values = append(values, fmt.Sprint(value))
+		}
+
+		if !strings.ContainsAny(strings.Join(values, ""), " ,") {
+			constraints = append(constraints, "oneof="+strings.Join(values, " "))
+		}
+	}
+
+	if len(constraints) > 0 && !param.Required {
+		rules = append(rules, "omitempty")
+	}
+
+	return strings.Join(append(rules, constraints...), ",")
+}
+
+// genAdapter generates the gin adapter binding the input of handler from every parameter source.
+func genAdapter(f *jen.File, handler *typedHandler, fields []bindingField) {
+	abort := func(status jen.Code) jen.Code {
+		return jen.Id("c").Dot("AbortWithStatusJSON").Call(status, jen.Qual(ginImportPath, "H").Values(jen.Dict{
+			jen.Lit("error"): jen.Id("err").Dot("Error").Call(),
+		}))
+	}
+	badRequest := []jen.Code{abort(jen.Qual(httpImportPath, "StatusBadRequest")), jen.Return()}
+
+	code := []jen.Code{jen.Var().Id("in").Id(handler.input)}
+	sources := []struct {
+		in   string
+		bind func(ptr jen.Code) jen.Code
+	}{
+		{"path", func(ptr jen.Code) jen.Code { return jen.Id("c").Dot("ShouldBindUri").Call(ptr) }},
+		{"query", func(ptr jen.Code) jen.Code { return jen.Id("c").Dot("ShouldBindQuery").Call(ptr) }},
+		{"header", func(ptr jen.Code) jen.Code { return jen.Id("c").Dot("ShouldBindHeader").Call(ptr) }},
+		{"formData", func(ptr jen.Code) jen.Code {
+			form := "Form"
+			for _, field := range fields {
+				if field.in == "formData" && (field.param.Type == "file" || field.param.Items != nil && field.param.Items.Type == "file") {
+					form = "FormMultipart"
+				}
+			}
+
+			return jen.Id("c").Dot("ShouldBindWith").Call(ptr, jen.Qual(ginImportPath+"/binding", form))
+		}},
+	}
+	for _, source := range sources {
+		var (
+			declared []jen.Code
+			assigned []jen.Code
+		)
+
+		// every source binds into its own struct, as gin validates the whole struct it binds
+		for _, field := range fields {
+			if field.in != source.in {
+				continue
+			}
+
+			declared = append(declared, jen.Id(field.name).Add(field.typ).Tag(field.tags))
+			assigned = append(assigned, jen.Id("in").Dot(field.name).Op("=").Id(source.in).Dot(field.name))
+		}
+
+		if len(declared) == 0 {
+			continue
+		}
+
+		code = append(code, jen.Var().Id(source.in).Struct(declared...))
+		code = append(code, jen.If(jen.Err().Op(":=").Add(source.bind(jen.Op("&").Id(source.in))), jen.Err().Op("!=").Nil()).Block(badRequest...))
+		code = append(code, assigned...)
+	}
+
+	for _, field := range fields {
+		if field.in != "body" {
+			continue
+		}
+
+		bind := jen.If(jen.Err().Op(":=").Id("c").Dot("ShouldBindJSON").Call(jen.Op("&").Id("in").Dot(field.name)), jen.Err().Op("!=").Nil()).Block(badRequest...)
+		if !field.param.Required {
+			bind = jen.If(jen.Id("c").Dot("Request").Dot("ContentLength").Op("!=").Lit(0)).Block(bind)
+		}
+
+		code = append(code, bind)
+	}
+
+	call := jen.Id(handler.name)
+	if handler.owner.Type != "" {
+		call = jen.Id("h").Dot(handler.name)
+	}
+
+	out := jen.Id("out")
+	if handler.success == http.StatusNoContent {
+		out = jen.Id("_")
+	}
+
+	code = append(code, jen.List(out, jen.Err()).Op(":=").Add(call).Call(jen.Id("c"), jen.Id("in")))
+
+	failure := []jen.Code{jen.Id("status").Op(":=").Qual(httpImportPath, "StatusInternalServerError")}
+	if len(handler.failures) > 0 {
+		var cases []jen.Code
+		for _, status := range handler.failures {
+			cases = append(cases, jen.Lit(status))
+		}
+
+		// only the @Failure status codes are rendered, any other error is an internal error
+		failure = append(failure,
+			jen.Var().Id("coded").Interface(jen.Id("StatusCode").Params().Int()),
+			jen.If(jen.Qual("errors", "As").Call(jen.Err(), jen.Op("&").Id("coded"))).Block(
+				jen.Switch(jen.Id("coded").Dot("StatusCode").Call()).Block(
+					jen.Case(cases...).Block(jen.Id("status").Op("=").Id("coded").Dot("StatusCode").Call()),
+				),
+			),
+		)
+	}
+
+	failure = append(failure, abort(jen.Id("status")), jen.Return())
+	code = append(code, jen.If(jen.Err().Op("!=").Nil()).Block(failure...))
+
+	if handler.success == http.StatusNoContent {
+		code = append(code, jen.Id("c").Dot("Status").Call(jen.Lit(handler.success)))
+	} else {
+		code = append(code, jen.Id("c").Dot("JSON").Call(jen.Lit(handler.success), jen.Id("out")))
+	}
+
+	f.Commentf("%s binds and validates %s, then calls %s and renders its documented responses.",
+		adapterName(handler.name), handler.input, handler.name)
+
+	fn := f.Func()
+	if handler.owner.Type != "" {
+		recv := jen.Id("h")
+		if handler.owner.Pointer {
+			recv = recv.Op("*")
+		}
+
+		fn = fn.Params(recv.Id(handler.owner.Type))
+	}
+
+	fn.Id(adapterName(handler.name)).Params(jen.Id("c").Op("*").Qual(ginImportPath, "Context")).Block(code...)
+}
@@ -0,0 +1,232 @@
+package swag
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseParamGoType(t *testing.T) {
+	t.Parallel()
+
+	src := `package api
+
+import "example.com/model"
+`
+	astFile, err := parser.ParseFile(token.NewFileSet(), "api.go", src, parser.ImportsOnly)
+	assert.NoError(t, err)
+
+	assert.Equal(t, &ParamGoType{ImportPath: "example.com/model", Type: "Account", Array: true}, parseParamGoType("[]model.Account", astFile))
+	assert.Equal(t, &ParamGoType{Type: "CreateUser"}, parseParamGoType("CreateUser", astFile))
+	assert.Equal(t, &ParamGoType{Type: "string"}, parseParamGoType("string", astFile))
+	assert.Nil(t, parseParamGoType("unknown.Account", astFile))
+	assert.Nil(t, parseParamGoType("map[string]string", astFile))
+	assert.Nil(t, parseParamGoType("model.Page{data=[]model.Account}", astFile))
+}
+
+func TestBindingValidation(t *testing.T) {
+	t.Parallel()
+
+	minimum, maximum := 1.0, 100.0
+	minLength := int64(3)
+
+	tests := []struct {
+		param    spec.Parameter
+		expected string
+	}{
+		{*spec.PathParam("id").Typed(INTEGER, ""), ""},
+		{*spec.PathParam("name").Typed(STRING, ""), "required"},
+		{*spec.QueryParam("verbose").Typed(BOOLEAN, "").AsRequired(), ""},
+		{*spec.QueryParam("offset").Typed(NUMBER, "").AsRequired(), ""},
+		{spec.Parameter{
+			ParamProps:   spec.ParamProps{Name: "limit", In: "query"},
+			SimpleSchema: spec.SimpleSchema{Type: INTEGER},
+			CommonValidations: spec.CommonValidations{
+				Minimum: &minimum, Maximum: &maximum, ExclusiveMaximum: true,
+			},
+		}, "omitempty,gte=1,lt=100"},
+		{spec.Parameter{
+			ParamProps:        spec.ParamProps{Name: "name", In: "query", Required: true},
+			SimpleSchema:      spec.SimpleSchema{Type: STRING},
+			CommonValidations: spec.CommonValidations{MinLength: &minLength, Enum: []interface{}{"asc", "desc"}},
+		}, "required,min=3,oneof=asc desc"},
+		{spec.Parameter{
+			ParamProps:        spec.ParamProps{Name: "page", In: "query", Required: true},
+			SimpleSchema:      spec.SimpleSchema{Type: INTEGER},
+			CommonValidations: spec.CommonValidations{Minimum: &minimum},
+		}, "gte=1"},
+		{*spec.BodyParam("user", nil).AsRequired(), "required"},
+		{*spec.BodyParam("count", spec.Int64Property()).AsRequired(), ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, bindingValidation(test.param), test.param.Name)
+	}
+}
+
+func TestBindingFields(t *testing.T) {
+	t.Parallel()
+
+	handler := &typedHandler{
+		name:  "UpdateUser",
+		input: "UpdateUserInput",
+		params: []spec.Parameter{
+			*spec.PathParam("id").Typed(INTEGER, "int64"),
+			*spec.QueryParam("page_size").Typed(INTEGER, "").WithDefault(20),
+			*spec.QueryParam("tags").CollectionOf(spec.NewItems().Typed(STRING, ""), "csv"),
+			*spec.HeaderParam("X-Request-Id").Typed(STRING, ""),
+			*spec.QueryParam("id").Typed(STRING, ""),
+			*spec.BodyParam("user", spec.RefSchema("#/definitions/model.User")).AsRequired(),
+		},
+		bodyType: &ParamGoType{ImportPath: "example.com/model", Type: "User"},
+	}
+
+	fields, err := bindingFields(handler)
+	assert.NoError(t, err)
+
+	var names []string
+	for _, field := range fields {
+		names = append(names, field.name)
+	}
+	assert.Equal(t, []string{"Id", "PageSize", "Tags", "XRequestId", "IdQuery", "Body"}, names)
+
+	assert.Equal(t, map[string]string{"uri": "id"}, fields[0].tags)
+	assert.Equal(t, map[string]string{"form": "page_size,default=20"}, fields[1].tags)
+	assert.Equal(t, map[string]string{"header": "X-Request-Id", "binding": "required"}, fields[3].tags)
+	assert.Equal(t, map[string]string{"json": "-", "binding": "required"}, fields[5].tags)
+
+	assert.Equal(t, "int64", jen.Add(fields[0].typ).GoString())
+	assert.Equal(t, "[]string", jen.Add(fields[2].typ).GoString())
+	assert.Equal(t, "json.RawMessage", jen.Add(bodyGoType(nil, nil)).GoString())
+	assert.Equal(t, "[]int", jen.Add(bodyGoType(&ParamGoType{Type: "int", Array: true}, nil)).GoString())
+}
+
+func TestGenAdapter(t *testing.T) {
+	t.Parallel()
+
+	handler := &typedHandler{
+		name:  "GetUser",
+		owner: HandlerReceiver{Type: "UserApi", Pointer: true},
+		input: "GetUserInput",
+		params: []spec.Parameter{
+			*spec.PathParam("id").Typed(INTEGER, ""),
+			*spec.BodyParam("filter", nil),
+		},
+		success:  http.StatusOK,
+		failures: []int{http.StatusNotFound},
+	}
+
+	fields, err := bindingFields(handler)
+	assert.NoError(t, err)
+
+	f := jen.NewFilePathName("example.com/api", "api")
+	f.ImportName(ginImportPath, "gin")
+	genAdapter(f, handler, fields)
+
+	var buf bytes.Buffer
+	assert.NoError(t, f.Render(&buf))
+
+	expected := `package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// GetUserHandler binds and validates GetUserInput, then calls GetUser and renders its documented responses.
+func (h *UserApi) GetUserHandler(c *gin.Context) {
+	var in GetUserInput
+	var path struct {
+		Id int ` + "`uri:\"id\"`" + `
+	}
+	if err := c.ShouldBindUri(&path); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	in.Id = path.Id
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&in.Body); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	out, err := h.GetUser(c, in)
+	if err != nil {
+		status := http.StatusInternalServerError
+		var coded interface {
+			StatusCode() int
+		}
+		if errors.As(err, &coded) {
+			switch coded.StatusCode() {
+			case 404:
+				status = coded.StatusCode()
+			}
+		}
+		c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, out)
+}
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestGenBindingFilesSharedInput(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	route := func(handler, pos string) RouteInfos {
+		return RouteInfos{
+			HandlerFun: "api." + handler,
+			handlerPkg: "example.com/api",
+			handlerDir: dir,
+			signature:  HandlerSignature{Kind: HandlerKindTyped, Input: "UserInput", Pos: pos},
+			operation:  &spec.Operation{},
+		}
+	}
+	routes := map[Routes][]RouteInfos{
+		{FilePath: dir}: {route("GetUser", "api.go:10"), route("UpdateUser", "api.go:20")},
+	}
+
+	err := genBindingFiles(routes, GenConfig{OutputDir: dir})
+	assert.EqualError(t, err, "api.go:20: UserInput is already the input of GetUser at api.go:10, declare an input type per handler")
+}
+
+func TestGenBindingFilesSamePackageBody(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	routes := map[Routes][]RouteInfos{
+		{FilePath: dir}: {{
+			HandlerFun: "api.CreateUser",
+			handlerPkg: "example.com/api",
+			handlerDir: dir,
+			signature:  HandlerSignature{Kind: HandlerKindTyped, Input: "CreateUserInput", Pos: "api.go:10"},
+			operation: &spec.Operation{OperationProps: spec.OperationProps{Parameters: []spec.Parameter{
+				*spec.BodyParam("body", spec.RefSchema("#/definitions/api.User")).AsRequired(),
+			}}},
+			bodyType: &ParamGoType{Type: "User"},
+		}},
+	}
+
+	require.NoError(t, genBindingFiles(routes, GenConfig{OutputDir: dir}))
+
+	content, err := os.ReadFile(filepath.Join(dir, bindingGenFile))
+	require.NoError(t, err)
+	assert.Regexp(t, `\tBody\s+User\s+`+"`", string(content))
+
+	astFile, err := parser.ParseFile(token.NewFileSet(), bindingGenFile, content, parser.ImportsOnly)
+	require.NoError(t, err)
+	for _, imp := range astFile.Imports {
+		assert.NotEqual(t, `""`, imp.Path.Value)
+	}
+}
@@ -3,8 +3,8 @@ package main
 import (
 	"fmt"
 	"github.com/CloverOS/swag-gin"
+	"github.com/CloverOS/swag-gin/format"
 	"github.com/CloverOS/swag-gin/gen"
-	"github.com/swaggo/swag/format"
 	"io/ioutil"
 	"log"
 	"os"
@@ -35,7 +35,20 @@ const (
 	autoCoverOld              = "autoCoverOld"
 	ginServerPackageFlag      = "ginServerPackage"
 	ginRouterPathFlag         = "ginRouterPath"
+	ginRouterLayoutFlag       = "ginRouterLayout"
+	routerBackendFlag         = "routerBackend"
+	routesSQLTemplateFlag     = "routesSQLTemplate"
+	tagsFlag                  = "tags"
+	parseExtensionFlag        = "parseExtension"
+	templateDelimsFlag        = "templateDelims"
+	packageNameFlag           = "packageName"
+	collectionFormatFlag      = "collectionFormat"
+	strictFlag                = "strict"
+	inferParamsFlag           = "inferParams"
+	inferResponsesFlag        = "inferResponses"
 	quietFlag                 = "quiet"
+	checkFlag                 = "check"
+	diffFlag                  = "diff"
 )
 
 var initFlags = []cli.Flag{
@@ -44,6 +57,10 @@ var initFlags = []cli.Flag{
 		Aliases: []string{"q"},
 		Usage:   "Make the logger quiet.",
 	},
+	&cli.BoolFlag{
+		Name:  checkFlag,
+		Usage: "Regenerate in memory and fail when the generated files on disk are out of date, nothing is written",
+	},
 	&cli.StringFlag{
 		Name:    generalInfoFlag,
 		Aliases: []string{"g"},
@@ -76,7 +93,7 @@ var initFlags = []cli.Flag{
 		Name:    outputTypesFlag,
 		Aliases: []string{"ot"},
 		Value:   "go,json,yaml",
-		Usage:   "Output types of generated files (docs.go, swagger.json, swagger.yaml) like go,json,yaml",
+		Usage:   "Output types of generated files (docs.go, swagger.json, swagger.yaml) like go,json,yaml, openapi3 for openapi.json and openapi.yaml in OpenAPI 3.0, and routes.json, routes.yaml, routes.sql exporting resource.go with autoRegisterGinRouter",
 	},
 	&cli.BoolFlag{
 		Name:  parseVendorFlag,
@@ -146,13 +163,72 @@ var initFlags = []cli.Flag{
 		Name:    ginServerPackageFlag,
 		Aliases: []string{"pkg"},
 		Value:   "router",
-		Usage:   "Router file package for gin web",
+		Usage:   "Package name of the aggregator router calling every generated module router",
 	},
 	&cli.StringFlag{
 		Name:    ginRouterPathFlag,
 		Aliases: []string{"rp"},
 		Value:   "./router.go",
-		Usage:   "Path for gen code file ",
+		Usage:   "Path of the aggregator router file, e.g. ./router/router.go to keep it out of the main package, empty to skip it",
+	},
+	&cli.StringFlag{
+		Name:    ginRouterLayoutFlag,
+		Aliases: []string{"rl"},
+		Value:   "parent",
+		Usage:   "Where to generate the router of each handler package: handler, parent, up:N, dir:PATH or a pattern like internal/{service}/router",
+	},
+	&cli.StringFlag{
+		Name:    routerBackendFlag,
+		Aliases: []string{"rb"},
+		Value:   "gin",
+		Usage:   "Web framework of the generated routers: gin, echo, chi or servemux (net/http.ServeMux of Go 1.22)",
+	},
+	&cli.StringFlag{
+		Name:    routesSQLTemplateFlag,
+		Aliases: []string{"rst"},
+		Usage:   "text/template file rendering the routes.sql output type, upserts into api_routes by (method, path) by default",
+	},
+	&cli.StringFlag{
+		Name:    tagsFlag,
+		Aliases: []string{"t"},
+		Value:   "",
+		Usage:   "A comma-separated list of tags to filter the APIs and the generated routers for which the documentation is generated. Special case if the tag is prefixed with the '!' character then the APIs with that tag will be excluded",
+	},
+	&cli.StringFlag{
+		Name:  parseExtensionFlag,
+		Value: "",
+		Usage: "Parse only those operations that match given extension",
+	},
+	&cli.StringFlag{
+		Name:    templateDelimsFlag,
+		Aliases: []string{"td"},
+		Value:   "",
+		Usage:   "Not supported, the generated docs.go depends on github.com/swaggo/swag v1.8.4 whose swag.Spec has no custom delimiters",
+	},
+	&cli.StringFlag{
+		Name:  packageNameFlag,
+		Value: "",
+		Usage: "A package name of docs.go and resource.go, using output directory name by default (check `--output` option)",
+	},
+	&cli.StringFlag{
+		Name:    collectionFormatFlag,
+		Aliases: []string{"cf"},
+		Value:   "csv",
+		Usage:   "Set default collection format",
+	},
+	&cli.BoolFlag{
+		Name:  strictFlag,
+		Usage: "When enabled, swag-gin fails on warnings that are most likely user errors, like a duplicated route",
+	},
+	&cli.BoolFlag{
+		Name:    inferParamsFlag,
+		Aliases: []string{"ip"},
+		Usage:   "Infer the path, query and body parameters gin handlers read with c.Param, c.Query, c.ShouldBindJSON... but do not annotate",
+	},
+	&cli.BoolFlag{
+		Name:    inferResponsesFlag,
+		Aliases: []string{"ir"},
+		Usage:   "Infer the responses gin handlers write with c.JSON or c.AbortWithStatusJSON but do not annotate, and warn when the annotated schema differs",
 	},
 }
 
@@ -169,6 +245,16 @@ func initAction(ctx *cli.Context) error {
 	if len(outputTypes) == 0 {
 		return fmt.Errorf("no output types specified")
 	}
+
+	if ctx.String(templateDelimsFlag) != "" {
+		return fmt.Errorf("--%s: %w", templateDelimsFlag, gen.ErrTemplateDelims)
+	}
+
+	collectionFormat := swag.TransToValidCollectionFormat(ctx.String(collectionFormatFlag))
+	if collectionFormat == "" {
+		return fmt.Errorf("not supported %s collectionFormat", ctx.String(collectionFormatFlag))
+	}
+
 	logger := log.New(os.Stdout, "", log.LstdFlags)
 	if ctx.Bool(quietFlag) {
 		logger = log.New(ioutil.Discard, "", log.LstdFlags)
@@ -196,6 +282,17 @@ func initAction(ctx *cli.Context) error {
 		AutoCoverOld:          ctx.Bool(autoCoverOld),
 		GinServerPackage:      ctx.String(ginServerPackageFlag),
 		GinRouterPath:         ctx.String(ginRouterPathFlag),
+		GinRouterLayout:       ctx.String(ginRouterLayoutFlag),
+		RouterBackend:         ctx.String(routerBackendFlag),
+		RoutesSQLTemplate:     ctx.String(routesSQLTemplateFlag),
+		Check:                 ctx.Bool(checkFlag),
+		Tags:                  ctx.String(tagsFlag),
+		ParseExtension:        ctx.String(parseExtensionFlag),
+		PackageName:           ctx.String(packageNameFlag),
+		CollectionFormat:      collectionFormat,
+		Strict:                ctx.Bool(strictFlag),
+		InferParams:           ctx.Bool(inferParamsFlag),
+		InferResponses:        ctx.Bool(inferResponsesFlag),
 		Debugger:              logger,
 	})
 }
@@ -225,6 +322,8 @@ func main() {
 					SearchDir: searchDir,
 					Excludes:  excludeDir,
 					MainFile:  mainFile,
+					Check:     c.Bool(checkFlag),
+					Diff:      c.Bool(diffFlag),
 				})
 			},
 			Flags: []cli.Flag{
@@ -232,7 +331,7 @@ func main() {
 					Name:    searchDirFlag,
 					Aliases: []string{"d"},
 					Value:   "./",
-					Usage:   "Directories you want to parse,comma separated and general-info file must be in the first one",
+					Usage:   "Directories you want to parse,comma separated and general-info file must be in the first one, - to format the standard input to the standard output",
 				},
 				&cli.StringFlag{
 					Name:  excludeFlag,
@@ -244,6 +343,14 @@ func main() {
 					Value:   "main.go",
 					Usage:   "Go file path in which 'swagger general API Info' is written",
 				},
+				&cli.BoolFlag{
+					Name:  checkFlag,
+					Usage: "List the files whose swag comments are not formatted and exit with a non-zero status, without writing them",
+				},
+				&cli.BoolFlag{
+					Name:  diffFlag,
+					Usage: "Print the formatting changes as unified diffs, without writing the files",
+				},
 			},
 		},
 	}
@@ -0,0 +1,241 @@
+// Package contract checks the routes, requests and responses served by gin against the docs generated by swag-gin.
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-openapi/spec"
+	"github.com/swaggo/swag"
+)
+
+// Violation is a part of a request or response that does not match the spec.
+type Violation struct {
+	// In is where the violating value was found: path, query, header, formData, body or status
+	In string `json:"in"`
+
+	// Field is the parameter name, or the location in the body like user.emails[0]
+	Field string `json:"field"`
+
+	// Message explains the violation
+	Message string `json:"message"`
+}
+
+// String returns the violation as field (in): message.
+func (v Violation) String() string {
+	if v.Field == "" {
+		return fmt.Sprintf("%s: %s", v.In, v.Message)
+	}
+
+	return fmt.Sprintf("%s (%s): %s", v.Field, v.In, v.Message)
+}
+
+// ErrorResponse is the body of the response rejecting a request, or a response with RejectResponseViolations.
+type ErrorResponse struct {
+	Error      string      `json:"error"`
+	Violations []Violation `json:"violations"`
+}
+
+// Contract holds the operations of a spec by method and path.
+type Contract struct {
+	swagger    *spec.Swagger
+	operations map[string]*spec.Operation
+	params     map[string][]spec.Parameter
+
+	// basePath is the group the routers are mounted on, the base path of the docs by default
+	basePath string
+
+	// routes caches the operation key of each gin route, empty for a route missing in the spec
+	routes sync.Map
+
+	onRequestViolations  func(c *gin.Context, violations []Violation)
+	onResponseViolations func(c *gin.Context, violations []Violation)
+}
+
+// SetMountPath replaces the base path of the docs, for routers mounted on another group, like /admin/api/v1.
+func SetMountPath(basePath string) func(*Contract) {
+	return func(contract *Contract) {
+		contract.basePath = basePath
+	}
+}
+
+// SetRequestViolationHandler replaces the 400 ErrorResponse written when a request violates the spec.
+func SetRequestViolationHandler(handler func(c *gin.Context, violations []Violation)) func(*Contract) {
+	return func(contract *Contract) {
+		contract.onRequestViolations = handler
+	}
+}
+
+// New returns the contract of doc, like the SwaggerInfo of a generated docs.go.
+func New(doc swag.Swagger, options ...func(*Contract)) (*Contract, error) {
+	return Parse([]byte(doc.ReadDoc()), options...)
+}
+
+// ParseFile returns the contract of a generated swagger.json.
+func ParseFile(path string, options ...func(*Contract)) (*Contract, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data, options...)
+}
+
+// Parse returns the contract of a swagger document.
+func Parse(data []byte, options ...func(*Contract)) (*Contract, error) {
+	var swagger spec.Swagger
+
+	err := json.Unmarshal(data, &swagger)
+	if err != nil {
+		return nil, fmt.Errorf("can not parse swagger document: %s", err)
+	}
+
+	contract := &Contract{
+		swagger:              &swagger,
+		operations:           make(map[string]*spec.Operation),
+		params:               make(map[string][]spec.Parameter),
+		basePath:             swagger.BasePath,
+		onRequestViolations:  writeViolations,
+		onResponseViolations: LogResponseViolations,
+	}
+
+	if swagger.Paths != nil {
+		for path, item := range swagger.Paths.Paths {
+			path = normalizePath(path)
+
+			for method, operation := range map[string]*spec.Operation{
+				http.MethodGet:     item.Get,
+				http.MethodPut:     item.Put,
+				http.MethodPost:    item.Post,
+				http.MethodDelete:  item.Delete,
+				http.MethodOptions: item.Options,
+				http.MethodHead:    item.Head,
+				http.MethodPatch:   item.Patch,
+			} {
+				if operation == nil {
+					continue
+				}
+
+				key := method + " " + path
+				contract.operations[key] = operation
+				contract.params[key] = contract.parameters(item.Parameters, operation.Parameters)
+			}
+		}
+	}
+
+	for _, option := range options {
+		option(contract)
+	}
+
+	return contract, nil
+}
+
+// parameters merges the parameters of a path with those of its operation, resolving #/parameters references.
+func (contract *Contract) parameters(pathParams, operationParams []spec.Parameter) []spec.Parameter {
+	var (
+		params  []spec.Parameter
+		indexes = make(map[string]int)
+	)
+
+	for _, param := range append(append([]spec.Parameter{}, pathParams...), operationParams...) {
+		if ref := param.Ref.String(); ref != "" {
+			resolved, ok := contract.swagger.Parameters[strings.TrimPrefix(ref, "#/parameters/")]
+			if !ok {
+				continue
+			}
+
+			param = resolved
+		}
+
+		// an operation parameter overrides the path parameter of the same name and location
+		key := param.In + " " + param.Name
+		if i, ok := indexes[key]; ok {
+			params[i] = param
+
+			continue
+		}
+
+		indexes[key] = len(params)
+		params = append(params, param)
+	}
+
+	return params
+}
+
+// operationKey returns the key of the operation served by the gin route fullPath, false when it is not in the spec.
+func (contract *Contract) operationKey(method, fullPath string) (string, bool) {
+	cacheKey := method + " " + fullPath
+	if key, ok := contract.routes.Load(cacheKey); ok {
+		return key.(string), key.(string) != ""
+	}
+
+	key := contract.findOperation(method, normalizePath(fullPath))
+	contract.routes.Store(cacheKey, key)
+
+	return key, key != ""
+}
+
+// findOperation matches the normalized path of a route against the spec paths, as is or without the base path
+// the routers are mounted on, see SetMountPath.
+func (contract *Contract) findOperation(method, path string) string {
+	candidates := []string{path}
+	if basePath := strings.TrimSuffix(contract.basePath, "/"); basePath != "" && strings.HasPrefix(path, basePath+"/") {
+		candidates = append(candidates, strings.TrimPrefix(path, basePath))
+	}
+
+	for _, candidate := range candidates {
+		if _, ok := contract.operations[method+" "+candidate]; ok {
+			return method + " " + candidate
+		}
+	}
+
+	return ""
+}
+
+// normalizePath turns the parameters of a spec path ({id}) and of a gin route (:id, *path) into {}.
+func normalizePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"), strings.HasPrefix(segment, "*"),
+			strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			segments[i] = "{}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func writeViolations(c *gin.Context, violations []Violation) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{
+		Error:      "request does not match the API contract",
+		Violations: violations,
+	})
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultContract *Contract
+	defaultErr      error
+)
+
+// Default returns the contract of the swagger document registered by the generated docs.go.
+func Default() (*Contract, error) {
+	defaultOnce.Do(func() {
+		doc, err := swag.ReadDoc()
+		if err != nil {
+			defaultErr = err
+
+			return
+		}
+
+		defaultContract, defaultErr = Parse([]byte(doc))
+	})
+
+	return defaultContract, defaultErr
+}
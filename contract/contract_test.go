@@ -0,0 +1,152 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDoc = `{
+    "swagger": "2.0",
+    "basePath": "/api/v1",
+    "paths": {
+        "/users": {
+            "get": {
+                "parameters": [
+                    {"type": "integer", "minimum": 1, "maximum": 100, "name": "limit", "in": "query"},
+                    {"enum": ["asc", "desc"], "type": "string", "name": "order", "in": "query"},
+                    {"type": "array", "items": {"type": "integer"}, "collectionFormat": "csv", "maxItems": 3, "name": "ids", "in": "query"}
+                ],
+                "responses": {"200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/model.User"}}}}
+            },
+            "post": {
+                "parameters": [
+                    {"name": "user", "in": "body", "required": true, "schema": {"$ref": "#/definitions/model.CreateUser"}},
+                    {"type": "string", "name": "X-Request-Id", "in": "header", "required": true}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/model.User"}},
+                    "400": {"description": "Bad Request", "schema": {"$ref": "#/definitions/model.Error"}}
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "parameters": [{"type": "integer", "name": "id", "in": "path", "required": true}],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/model.User"}},
+                    "404": {"description": "Not Found", "schema": {"$ref": "#/definitions/model.Error"}}
+                }
+            },
+            "delete": {
+                "parameters": [{"type": "integer", "name": "id", "in": "path", "required": true}],
+                "responses": {"204": {"description": "No Content"}}
+            }
+        },
+        "/files/{path}": {
+            "get": {
+                "parameters": [{"type": "string", "pattern": "^[a-z/]+$", "name": "path", "in": "path", "required": true}],
+                "responses": {"200": {"description": "OK"}}
+            }
+        }
+    },
+    "definitions": {
+        "model.CreateUser": {
+            "type": "object",
+            "required": ["name"],
+            "properties": {
+                "name": {"type": "string", "minLength": 2, "maxLength": 16},
+                "role": {"type": "string", "enum": ["admin", "member"]},
+                "age": {"type": "integer", "minimum": 0},
+                "emails": {"type": "array", "items": {"type": "string"}}
+            }
+        },
+        "model.User": {
+            "type": "object",
+            "properties": {
+                "id": {"type": "integer"},
+                "name": {"type": "string"}
+            }
+        },
+        "model.Error": {
+            "type": "object",
+            "properties": {
+                "error": {"type": "string"}
+            }
+        }
+    }
+}`
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	contract, err := Parse([]byte(testDoc))
+	assert.NoError(t, err)
+	assert.Len(t, contract.operations, 5)
+	assert.Equal(t, "/api/v1", contract.basePath)
+
+	_, err = Parse([]byte("{"))
+	assert.Error(t, err)
+}
+
+func TestOperationKey(t *testing.T) {
+	t.Parallel()
+
+	contract, err := Parse([]byte(testDoc))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		method   string
+		fullPath string
+		expected string
+	}{
+		{"GET", "/users/:id", "GET /users/{}"},
+		{"GET", "/api/v1/users/:id", "GET /users/{}"},
+		{"GET", "/admin/api/v1/users", ""},
+		{"GET", "/v2/users", ""},
+		{"GET", "/api/v1/files/*path", "GET /files/{}"},
+		{"DELETE", "/users/:user", "DELETE /users/{}"},
+		{"PUT", "/users/:id", ""},
+		{"GET", "/health", ""},
+		{"GET", "", ""},
+	}
+	for _, test := range tests {
+		key, ok := contract.operationKey(test.method, test.fullPath)
+		assert.Equal(t, test.expected, key, test.method+" "+test.fullPath)
+		assert.Equal(t, test.expected != "", ok)
+	}
+}
+
+func TestOperationKeyMountPath(t *testing.T) {
+	t.Parallel()
+
+	contract, err := Parse([]byte(testDoc), SetMountPath("/admin/api/v1"))
+	assert.NoError(t, err)
+
+	key, ok := contract.operationKey("GET", "/admin/api/v1/users/:id")
+	assert.True(t, ok)
+	assert.Equal(t, "GET /users/{}", key)
+
+	_, ok = contract.operationKey("GET", "/api/v1/users/:id")
+	assert.False(t, ok)
+}
+
+func TestNormalizePath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/users/{}/files/{}", normalizePath("/users/{id}/files/{path}"))
+	assert.Equal(t, "/users/{}/files/{}", normalizePath("/users/:id/files/*path"))
+	assert.Equal(t, "/users", normalizePath("/users"))
+}
+
+func TestViolationString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "limit (query): is required", Violation{In: "query", Field: "limit", Message: "is required"}.String())
+	assert.Equal(t, "body: is required", Violation{In: "body", Message: "is required"}.String())
+}
@@ -0,0 +1,305 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-openapi/spec"
+)
+
+// maxMultipartMemory is the memory used to parse a multipart form, like gin.Engine.MaxMultipartMemory.
+const maxMultipartMemory = 32 << 20
+
+// Requests returns a gin middleware rejecting the requests that do not match the operation of their route,
+// with a 400 ErrorResponse listing every violation. Routes missing in the spec are not checked.
+func (contract *Contract) Requests() gin.HandlerFunc {
+	return contract.validateRequest
+}
+
+func (contract *Contract) validateRequest(c *gin.Context) {
+	violations, err := contract.CheckRequest(c)
+	if err != nil {
+		// an unreadable body or form is reported like any other violation
+		violations = []Violation{{In: "body", Message: "can not be read: " + err.Error()}}
+	}
+
+	if len(violations) > 0 {
+		contract.onRequestViolations(c, violations)
+
+		return
+	}
+
+	c.Next()
+}
+
+// ValidateRequest is a gin middleware checking a request against the document registered by the generated
+// docs.go, to be declared on single routes by @Middleware contract.ValidateRequest.
+func ValidateRequest(c *gin.Context) {
+	contract, err := Default()
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+
+		return
+	}
+
+	contract.validateRequest(c)
+}
+
+// CheckRequest returns the violations of the request in c against the operation of its route,
+// restoring the body for the handler. The parameters, types, enums, bounds, lengths, patterns
+// and the JSON body are checked.
+func (contract *Contract) CheckRequest(c *gin.Context) ([]Violation, error) {
+	key, ok := contract.operationKey(c.Request.Method, c.FullPath())
+	if !ok {
+		return nil, nil
+	}
+
+	var violations []Violation
+	for _, param := range contract.params[key] {
+		if param.In == "body" {
+			bodyViolations, err := contract.validateBody(c, param)
+			if err != nil {
+				return nil, err
+			}
+
+			violations = append(violations, bodyViolations...)
+
+			continue
+		}
+
+		values, present, err := paramValues(c, param)
+		if err != nil {
+			return nil, err
+		}
+
+		if !present {
+			if param.Required {
+				violations = append(violations, Violation{In: param.In, Field: param.Name, Message: "is required"})
+			}
+
+			continue
+		}
+
+		violations = append(violations, validateParam(param, values)...)
+	}
+
+	return violations, nil
+}
+
+// paramValues returns the raw values of a non-body parameter, split by its collection format.
+func paramValues(c *gin.Context, param spec.Parameter) ([]string, bool, error) {
+	var values []string
+
+	switch param.In {
+	case "path":
+		for _, p := range c.Params {
+			if p.Key == param.Name {
+				values = []string{strings.TrimPrefix(p.Value, "/")}
+			}
+		}
+	case "query":
+		values = c.Request.URL.Query()[param.Name]
+	case "header":
+		values = c.Request.Header.Values(param.Name)
+	case "formData":
+		err := c.Request.ParseMultipartForm(maxMultipartMemory)
+		if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return nil, false, err
+		}
+
+		if param.Type == "file" {
+			if c.Request.MultipartForm == nil || len(c.Request.MultipartForm.File[param.Name]) == 0 {
+				return nil, false, nil
+			}
+
+			return nil, true, nil
+		}
+
+		values = c.Request.PostForm[param.Name]
+	}
+
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+
+	if param.Type == "array" && param.CollectionFormat != "multi" {
+		return splitCollection(values[0], param.CollectionFormat), true, nil
+	}
+
+	return values, true, nil
+}
+
+func splitCollection(value, format string) []string {
+	if value == "" {
+		return nil
+	}
+
+	switch format {
+	case "ssv":
+		return strings.Split(value, " ")
+	case "tsv":
+		return strings.Split(value, "\t")
+	case "pipes":
+		return strings.Split(value, "|")
+	}
+
+	return strings.Split(value, ",")
+}
+
+// validateParam returns the violations of the values of a non-body parameter.
+func validateParam(param spec.Parameter, values []string) []Violation {
+	if param.Type == "file" {
+		return nil
+	}
+
+	violations := func(items []Violation) []Violation {
+		for i := range items {
+			items[i].In = param.In
+		}
+
+		return items
+	}
+
+	if param.Type != "array" {
+		return violations(validateSimple(param.Name, values[0], param.Type, &param.CommonValidations))
+	}
+
+	result := validateItems(len(values), param.Name, param.MinItems, param.MaxItems)
+	if param.UniqueItems {
+		seen := make(map[string]bool, len(values))
+		for _, value := range values {
+			if seen[value] {
+				result = append(result, Violation{Field: param.Name, Message: "must not contain duplicate items"})
+
+				break
+			}
+
+			seen[value] = true
+		}
+	}
+
+	if param.Items != nil {
+		for i, value := range values {
+			field := fmt.Sprintf("%s[%d]", param.Name, i)
+			result = append(result, validateSimple(field, value, param.Items.Type, &param.Items.CommonValidations)...)
+		}
+	}
+
+	return violations(result)
+}
+
+// validateSimple converts the raw value of a parameter or array item to typ and checks its constraints.
+func validateSimple(field, raw, typ string, validations *spec.CommonValidations) []Violation {
+	var value interface{} = raw
+
+	switch typ {
+	case "integer":
+		number, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return []Violation{{Field: field, Message: "must be an integer, got " + strconv.Quote(raw)}}
+		}
+
+		value = number
+	case "number":
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return []Violation{{Field: field, Message: "must be a number, got " + strconv.Quote(raw)}}
+		}
+
+		value = number
+	case "boolean":
+		boolean, err := strconv.ParseBool(raw)
+		if err != nil {
+			return []Violation{{Field: field, Message: "must be a boolean, got " + strconv.Quote(raw)}}
+		}
+
+		value = boolean
+	}
+
+	var violations []Violation
+	if len(validations.Enum) > 0 && !inEnum(validations.Enum, value) {
+		violations = append(violations, Violation{Field: field, Message: "must be one of " + enumString(validations.Enum)})
+	}
+
+	switch value := value.(type) {
+	case string:
+		violations = append(violations, validateString(value, field, validations.MinLength, validations.MaxLength, validations.Pattern)...)
+	case int64:
+		violations = append(violations, validateNumber(float64(value), field, validations)...)
+	case float64:
+		violations = append(violations, validateNumber(value, field, validations)...)
+	}
+
+	return violations
+}
+
+// validateBody checks a JSON body against the schema of param, putting the read body back for the handler.
+func (contract *Contract) validateBody(c *gin.Context, param spec.Parameter) ([]Violation, error) {
+	var data []byte
+	if c.Request.Body != nil {
+		var err error
+
+		data, err = io.ReadAll(c.Request.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		if param.Required {
+			return []Violation{{In: "body", Message: "is required"}}, nil
+		}
+
+		return nil, nil
+	}
+
+	if contentType := c.ContentType(); contentType != "" {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		if mediaType != gin.MIMEJSON && !strings.HasSuffix(mediaType, "+json") {
+			return nil, nil
+		}
+	}
+
+	value, err := decodeJSON(data)
+	if err != nil {
+		return []Violation{{In: "body", Message: "must be valid JSON: " + err.Error()}}, nil
+	}
+
+	validator := schemaValidator{definitions: contract.swagger.Definitions}
+
+	violations := validator.validate(param.Schema, value, "")
+	for i := range violations {
+		violations[i].In = "body"
+	}
+
+	return violations, nil
+}
+
+// decodeJSON decodes data keeping numbers as json.Number, so integers are told apart from numbers.
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+
+	return value, nil
+}
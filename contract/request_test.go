@@ -0,0 +1,164 @@
+package contract
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRequestEngine(t *testing.T, contract *Contract) *gin.Engine {
+	t.Helper()
+
+	handler := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	}
+
+	engine := gin.New()
+	api := engine.Group("/api/v1", contract.Requests())
+	api.GET("/users", handler)
+	api.POST("/users", handler)
+	api.GET("/users/:id", handler)
+	api.GET("/files/*path", handler)
+	api.GET("/health", handler)
+
+	return engine
+}
+
+func TestRequests(t *testing.T) {
+	t.Parallel()
+
+	contract, err := Parse([]byte(testDoc))
+	assert.NoError(t, err)
+
+	engine := newRequestEngine(t, contract)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		header     map[string]string
+		violations []Violation
+	}{
+		{name: "valid query", method: "GET", target: "/api/v1/users?limit=10&order=asc&ids=1,2"},
+		{name: "undocumented route", method: "GET", target: "/api/v1/health?limit=x"},
+		{
+			name: "invalid query", method: "GET", target: "/api/v1/users?limit=0&order=random&ids=1,a,3,4",
+			violations: []Violation{
+				{In: "query", Field: "limit", Message: "must be greater than or equal to 1"},
+				{In: "query", Field: "order", Message: "must be one of [asc, desc]"},
+				{In: "query", Field: "ids", Message: "must have at most 3 items"},
+				{In: "query", Field: "ids[1]", Message: `must be an integer, got "a"`},
+			},
+		},
+		{
+			name: "invalid path", method: "GET", target: "/api/v1/users/abc",
+			violations: []Violation{{In: "path", Field: "id", Message: `must be an integer, got "abc"`}},
+		},
+		{
+			name: "catch-all path", method: "GET", target: "/api/v1/files/A.txt",
+			violations: []Violation{{In: "path", Field: "path", Message: "must match the pattern ^[a-z/]+$"}},
+		},
+		{
+			name: "valid body", method: "POST", target: "/api/v1/users",
+			body:   `{"name": "gopher", "role": "admin", "emails": ["gopher@example.com"], "extra": true}`,
+			header: map[string]string{"X-Request-Id": "1", "Content-Type": "application/json"},
+		},
+		{
+			name: "missing body and header", method: "POST", target: "/api/v1/users",
+			violations: []Violation{
+				{In: "body", Message: "is required"},
+				{In: "header", Field: "X-Request-Id", Message: "is required"},
+			},
+		},
+		{
+			name: "invalid body", method: "POST", target: "/api/v1/users",
+			body:   `{"name": "g", "role": "owner", "age": 1.5, "emails": [1]}`,
+			header: map[string]string{"X-Request-Id": "1"},
+			violations: []Violation{
+				{In: "body", Field: "age", Message: "must be an integer, got a number"},
+				{In: "body", Field: "emails[0]", Message: "must be a string, got a number"},
+				{In: "body", Field: "name", Message: "must be at least 2 characters long"},
+				{In: "body", Field: "role", Message: "must be one of [admin, member]"},
+			},
+		},
+		{
+			name: "malformed body", method: "POST", target: "/api/v1/users",
+			body:       `{"name": `,
+			header:     map[string]string{"X-Request-Id": "1"},
+			violations: []Violation{{In: "body", Message: "must be valid JSON: unexpected EOF"}},
+		},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+		for name, value := range test.header {
+			req.Header.Set(name, value)
+		}
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		if test.violations == nil {
+			assert.Equal(t, http.StatusOK, w.Code, test.name)
+			assert.Equal(t, test.body, w.Body.String(), test.name)
+
+			continue
+		}
+
+		var resp ErrorResponse
+		assert.Equal(t, http.StatusBadRequest, w.Code, test.name)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), test.name)
+		assert.Equal(t, test.violations, resp.Violations, test.name)
+	}
+}
+
+func TestRequestsUnreadableBody(t *testing.T) {
+	t.Parallel()
+
+	contract, err := Parse([]byte(testDoc))
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/api/v1/users", iotest.ErrReader(io.ErrUnexpectedEOF))
+	req.Header.Set("X-Request-Id", "1")
+
+	w := httptest.NewRecorder()
+	newRequestEngine(t, contract).ServeHTTP(w, req)
+
+	var resp ErrorResponse
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []Violation{{In: "body", Message: "can not be read: unexpected EOF"}}, resp.Violations)
+}
+
+func TestSetRequestViolationHandler(t *testing.T) {
+	t.Parallel()
+
+	contract, err := Parse([]byte(testDoc), SetRequestViolationHandler(func(c *gin.Context, violations []Violation) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"message": violations[0].String()})
+	}))
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	newRequestEngine(t, contract).ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/users/abc", nil))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"message": "id (path): must be an integer, got \"abc\""}`, w.Body.String())
+}
+
+func TestSplitCollection(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"a", "b"}, splitCollection("a,b", ""))
+	assert.Equal(t, []string{"a", "b"}, splitCollection("a b", "ssv"))
+	assert.Equal(t, []string{"a", "b"}, splitCollection("a\tb", "tsv"))
+	assert.Equal(t, []string{"a", "b"}, splitCollection("a|b", "pipes"))
+	assert.Nil(t, splitCollection("", "csv"))
+}
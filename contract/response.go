@@ -0,0 +1,216 @@
+package contract
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetResponseViolationHandler replaces LogResponseViolations, called with the responses that violate the spec.
+func SetResponseViolationHandler(handler func(c *gin.Context, violations []Violation)) func(*Contract) {
+	return func(contract *Contract) {
+		contract.onResponseViolations = handler
+	}
+}
+
+// LogResponseViolations logs every violation of a response, which is then sent as written by the handler.
+func LogResponseViolations(c *gin.Context, violations []Violation) {
+	for _, violation := range violations {
+		log.Printf("[contract] %s %s responded %d: %s", c.Request.Method, c.Request.URL.Path, c.Writer.Status(), violation)
+	}
+}
+
+// RejectResponseViolations replaces a response violating the spec with a 500 ErrorResponse, so integration
+// tests asserting the status code catch it.
+func RejectResponseViolations(c *gin.Context, violations []Violation) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{
+		Error:      "response does not match the API contract",
+		Violations: violations,
+	})
+}
+
+// Responses returns a gin middleware checking the status code and JSON body written by the handlers against
+// the @Success and @Failure responses of their operation, including properties missing in the documented schema.
+// The response is held back until the handler returns, so it is meant for development and test environments.
+func (contract *Contract) Responses() gin.HandlerFunc {
+	return contract.validateResponse
+}
+
+// ValidateResponse is a gin middleware checking a response against the document registered by the generated
+// docs.go, to be declared on single routes by @Middleware contract.ValidateResponse.
+func ValidateResponse(c *gin.Context) {
+	contract, err := Default()
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+
+		return
+	}
+
+	contract.validateResponse(c)
+}
+
+func (contract *Contract) validateResponse(c *gin.Context) {
+	key, ok := contract.operationKey(c.Request.Method, c.FullPath())
+	if !ok {
+		c.Next()
+
+		return
+	}
+
+	writer := c.Writer
+	recorder := &responseRecorder{ResponseWriter: writer, status: http.StatusOK, size: -1}
+	c.Writer = recorder
+
+	c.Next()
+
+	c.Writer = writer
+	if recorder.hijacked {
+		return
+	}
+
+	violations := contract.CheckResponse(key, recorder.status, recorder.Header(), recorder.body.Bytes())
+	if len(violations) > 0 {
+		// a handler replacing the response sets its own length
+		contentLength := writer.Header().Get("Content-Length")
+		writer.Header().Del("Content-Length")
+
+		writer.WriteHeader(recorder.status)
+		contract.onResponseViolations(c, violations)
+		if writer.Written() {
+			return
+		}
+
+		if contentLength != "" {
+			writer.Header().Set("Content-Length", contentLength)
+		}
+	}
+
+	writer.WriteHeader(recorder.status)
+	if recorder.Written() {
+		writer.WriteHeaderNow()
+		_, _ = writer.Write(recorder.body.Bytes())
+	}
+}
+
+// CheckResponse returns the violations of a response with status, header and body to the operation
+// key, the method and normalized path of a spec operation like GET /users/{}.
+func (contract *Contract) CheckResponse(key string, status int, header http.Header, body []byte) []Violation {
+	operation, ok := contract.operations[key]
+	if !ok || operation.Responses == nil {
+		return nil
+	}
+
+	response, ok := operation.Responses.StatusCodeResponses[status]
+	if !ok {
+		if operation.Responses.Default == nil {
+			return []Violation{{In: "status", Message: fmt.Sprintf("%d is not a documented response status", status)}}
+		}
+
+		response = *operation.Responses.Default
+	}
+
+	if response.Ref.String() != "" {
+		resolved, ok := contract.swagger.Responses[strings.TrimPrefix(response.Ref.String(), "#/responses/")]
+		if !ok {
+			return nil
+		}
+
+		response = resolved
+	}
+
+	if response.Schema == nil || strings.HasPrefix(key, http.MethodHead+" ") {
+		return nil
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return []Violation{{In: "body", Message: fmt.Sprintf("is empty, %d documents a body", status)}}
+	}
+
+	if contentType := header.Get("Content-Type"); contentType != "" {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		if mediaType != gin.MIMEJSON && !strings.HasSuffix(mediaType, "+json") {
+			return nil
+		}
+	}
+
+	value, err := decodeJSON(body)
+	if err != nil {
+		return []Violation{{In: "body", Message: "must be valid JSON: " + err.Error()}}
+	}
+
+	validator := schemaValidator{definitions: contract.swagger.Definitions, undeclared: true}
+
+	violations := validator.validate(response.Schema, value, "")
+	for i := range violations {
+		violations[i].In = "body"
+	}
+
+	return violations
+}
+
+// responseRecorder holds back the status and body written by a handler, like gin's own response writer.
+type responseRecorder struct {
+	gin.ResponseWriter
+
+	status   int
+	size     int
+	body     bytes.Buffer
+	hijacked bool
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if code > 0 && !r.Written() {
+		r.status = code
+	}
+}
+
+func (r *responseRecorder) WriteHeaderNow() {
+	if !r.Written() {
+		r.size = 0
+	}
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.WriteHeaderNow()
+	n, err := r.body.Write(data)
+	r.size += n
+
+	return n, err
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.WriteHeaderNow()
+	n, err := r.body.WriteString(s)
+	r.size += n
+
+	return n, err
+}
+
+func (r *responseRecorder) Status() int {
+	return r.status
+}
+
+func (r *responseRecorder) Size() int {
+	return r.size
+}
+
+func (r *responseRecorder) Written() bool {
+	return r.size != -1
+}
+
+// Flush does nothing, the body is sent once checked.
+func (r *responseRecorder) Flush() {}
+
+// Hijack hands the connection to the handler, whose response is then not checked.
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+
+	return r.ResponseWriter.Hijack()
+}
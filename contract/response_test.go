@@ -0,0 +1,137 @@
+package contract
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newResponseEngine(t *testing.T, contract *Contract) *gin.Engine {
+	t.Helper()
+
+	engine := gin.New()
+	api := engine.Group("/api/v1", contract.Responses())
+	api.GET("/users", func(c *gin.Context) {
+		c.JSON(http.StatusOK, []gin.H{{"id": 1, "name": "gopher"}})
+	})
+	api.POST("/users", func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"id": 1, "name": "gopher", "password": "secret"})
+	})
+	api.GET("/users/:id", func(c *gin.Context) {
+		switch c.Param("id") {
+		case "1":
+			c.JSON(http.StatusOK, gin.H{"id": "1"})
+		case "2":
+			c.AbortWithStatus(http.StatusTeapot)
+		default:
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		}
+	})
+	api.DELETE("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	api.GET("/files/*path", func(c *gin.Context) {
+		c.String(http.StatusOK, "content")
+	})
+
+	return engine
+}
+
+func TestResponses(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		reported = make(map[string][]Violation)
+	)
+
+	contract, err := Parse([]byte(testDoc), SetResponseViolationHandler(func(c *gin.Context, violations []Violation) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		reported[c.Request.Method+" "+c.Request.URL.Path] = violations
+	}))
+	assert.NoError(t, err)
+
+	engine := newResponseEngine(t, contract)
+
+	tests := []struct {
+		method     string
+		target     string
+		status     int
+		body       string
+		violations []Violation
+	}{
+		{method: "GET", target: "/api/v1/users", status: http.StatusOK, body: `[{"id":1,"name":"gopher"}]`},
+		{method: "GET", target: "/api/v1/users/3", status: http.StatusNotFound, body: `{"error":"user not found"}`},
+		{method: "DELETE", target: "/api/v1/users/3", status: http.StatusNoContent},
+		{method: "GET", target: "/api/v1/files/a", status: http.StatusOK, body: "content"},
+		{
+			method: "POST", target: "/api/v1/users", status: http.StatusCreated, body: `{"id":1,"name":"gopher","password":"secret"}`,
+			violations: []Violation{{In: "body", Field: "password", Message: "is not a documented property"}},
+		},
+		{
+			method: "GET", target: "/api/v1/users/1", status: http.StatusOK, body: `{"id":"1"}`,
+			violations: []Violation{{In: "body", Field: "id", Message: "must be an integer, got a string"}},
+		},
+		{
+			method: "GET", target: "/api/v1/users/2", status: http.StatusTeapot,
+			violations: []Violation{{In: "status", Message: "418 is not a documented response status"}},
+		},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(test.method, test.target, nil))
+
+		name := test.method + " " + test.target
+		assert.Equal(t, test.status, w.Code, name)
+		assert.Equal(t, test.body, w.Body.String(), name)
+
+		mu.Lock()
+		assert.Equal(t, test.violations, reported[name], name)
+		mu.Unlock()
+	}
+}
+
+func TestRejectResponseViolations(t *testing.T) {
+	t.Parallel()
+
+	contract, err := Parse([]byte(testDoc), SetResponseViolationHandler(RejectResponseViolations))
+	assert.NoError(t, err)
+
+	engine := newResponseEngine(t, contract)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/users", nil))
+
+	var resp ErrorResponse
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "response does not match the API contract", resp.Error)
+	assert.Equal(t, []Violation{{In: "body", Field: "password", Message: "is not a documented property"}}, resp.Violations)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/users", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCheckResponse(t *testing.T) {
+	t.Parallel()
+
+	contract, err := Parse([]byte(testDoc))
+	assert.NoError(t, err)
+
+	jsonHeader := http.Header{"Content-Type": []string{gin.MIMEJSON}}
+
+	assert.Empty(t, contract.CheckResponse("GET /users/{}", http.StatusOK, jsonHeader, []byte(`{"id": 1}`)))
+	assert.Empty(t, contract.CheckResponse("GET /health", http.StatusTeapot, jsonHeader, nil))
+	assert.Equal(t, []Violation{{In: "body", Message: "is empty, 200 documents a body"}},
+		contract.CheckResponse("GET /users/{}", http.StatusOK, jsonHeader, nil))
+	assert.Equal(t, []Violation{{In: "body", Message: "must be valid JSON: invalid character 'o' in literal null (expecting 'u')"}},
+		contract.CheckResponse("GET /users/{}", http.StatusOK, jsonHeader, []byte("not json")))
+}
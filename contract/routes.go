@@ -0,0 +1,171 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Route is a route of the docs or of a gin engine.
+type Route struct {
+	// Method is the HTTP method in upper case
+	Method string `json:"method"`
+
+	// Path is the full path in gin route syntax, with the base path of the docs
+	Path string `json:"path"`
+
+	// Handler is the handler function, as annotated in the docs or as named by gin
+	Handler string `json:"handler"`
+}
+
+// String returns the route as method path (handler).
+func (route Route) String() string {
+	return fmt.Sprintf("%s %s (%s)", route.Method, route.Path, route.Handler)
+}
+
+// RouteDriftError lists the differences between the routes of the docs and the routes of a gin engine.
+type RouteDriftError struct {
+	// Unregistered are documented routes the engine does not serve
+	Unregistered []Route `json:"unregistered"`
+
+	// Undocumented are routes of the engine missing in the docs
+	Undocumented []Route `json:"undocumented"`
+}
+
+func (e *RouteDriftError) Error() string {
+	lines := []string{fmt.Sprintf("the docs and the router differ by %d routes:", len(e.Unregistered)+len(e.Undocumented))}
+	for _, route := range e.Unregistered {
+		lines = append(lines, "\tdocumented but not registered: "+route.String())
+	}
+
+	for _, route := range e.Undocumented {
+		lines = append(lines, "\tregistered but not documented: "+route.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// RouteCheck holds the options of CheckRoutes.
+type RouteCheck struct {
+	basePath *string
+	ignored  map[string]bool
+}
+
+// SetBasePath replaces the base path of the docs, for routers mounted on another group.
+func SetBasePath(basePath string) func(*RouteCheck) {
+	return func(check *RouteCheck) {
+		check.basePath = &basePath
+	}
+}
+
+// IgnoreRoutes leaves out the engine routes of the given paths, like /swagger/*any, whatever their method.
+func IgnoreRoutes(paths ...string) func(*RouteCheck) {
+	return func(check *RouteCheck) {
+		for _, path := range paths {
+			check.ignored[path] = true
+		}
+	}
+}
+
+// documentedRoute holds the fields of the RouteInfos of a generated resource.go read by CheckRoutes.
+type documentedRoute struct {
+	Method     string `json:"method"`
+	GinPath    string `json:"gin_path"`
+	BasePath   string `json:"base_path"`
+	HandlerFun string `json:"handler_fun"`
+}
+
+// CheckRoutes compares the routes registered on engine with infos, the result of GetRouteInfos of the generated
+// resource.go, and returns a *RouteDriftError when a documented route is not registered or a registered route
+// is not documented. Routes are matched by method and path, wildcards match whatever their name.
+func CheckRoutes(engine *gin.Engine, infos interface{}, options ...func(*RouteCheck)) error {
+	check := &RouteCheck{ignored: make(map[string]bool)}
+	for _, option := range options {
+		option(check)
+	}
+
+	// the RouteInfos type is generated in the docs package, read it through its json tags
+	data, err := json.Marshal(infos)
+	if err != nil {
+		return fmt.Errorf("can not read route infos: %s", err)
+	}
+
+	var documented []documentedRoute
+
+	err = json.Unmarshal(data, &documented)
+	if err != nil {
+		return fmt.Errorf("can not read route infos: %s", err)
+	}
+
+	docs := make(map[string]Route)
+	for _, info := range documented {
+		basePath := info.BasePath
+		if check.basePath != nil {
+			basePath = *check.basePath
+		}
+
+		route := Route{
+			Method:  strings.ToUpper(info.Method),
+			Path:    joinPaths(basePath, info.GinPath),
+			Handler: info.HandlerFun,
+		}
+		docs[route.Method+" "+normalizePath(route.Path)] = route
+	}
+
+	drift := &RouteDriftError{}
+
+	for _, info := range engine.Routes() {
+		if check.ignored[info.Path] {
+			continue
+		}
+
+		key := info.Method + " " + normalizePath(info.Path)
+		if _, ok := docs[key]; ok {
+			delete(docs, key)
+
+			continue
+		}
+
+		drift.Undocumented = append(drift.Undocumented, Route{Method: info.Method, Path: info.Path, Handler: info.Handler})
+	}
+
+	for _, route := range docs {
+		drift.Unregistered = append(drift.Unregistered, route)
+	}
+
+	if len(drift.Unregistered) == 0 && len(drift.Undocumented) == 0 {
+		return nil
+	}
+
+	sortRoutes(drift.Unregistered)
+	sortRoutes(drift.Undocumented)
+
+	return drift
+}
+
+// joinPaths appends path to basePath the way a gin group does, keeping a trailing slash of path.
+func joinPaths(basePath, path string) string {
+	basePath = strings.TrimSuffix(basePath, "/")
+	if path == "" || path == "/" {
+		if basePath == "" {
+			return "/"
+		}
+
+		return basePath + path
+	}
+
+	return basePath + "/" + strings.TrimPrefix(path, "/")
+}
+
+func sortRoutes(routes []Route) {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+
+		return routes[i].Method < routes[j].Method
+	})
+}
@@ -0,0 +1,79 @@
+package contract
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// RouteGroup and RouteInfos mirror the types of a generated resource.go.
+type RouteGroup struct {
+	GroupName string `json:"name"`
+}
+
+type RouteInfos struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	GinPath    string `json:"gin_path"`
+	BasePath   string `json:"base_path"`
+	HandlerFun string `json:"handler_fun"`
+	RouteGroup
+}
+
+var routeInfos = []RouteInfos{
+	{Method: "get", Path: "/users", GinPath: "/users", BasePath: "/api/v1", HandlerFun: "api.ListUsers", RouteGroup: RouteGroup{GroupName: "users"}},
+	{Method: "get", Path: "/users/{id}", GinPath: "/users/:id", BasePath: "/api/v1", HandlerFun: "api.GetUser", RouteGroup: RouteGroup{GroupName: "users"}},
+	{Method: "delete", Path: "/users/{id}", GinPath: "/users/:id", BasePath: "/api/v1", HandlerFun: "api.DeleteUser", RouteGroup: RouteGroup{GroupName: "users"}},
+	{Method: "get", Path: "/files/{path}", GinPath: "/files/*path", BasePath: "/api/v1", HandlerFun: "api.GetFile", RouteGroup: RouteGroup{GroupName: "files"}},
+}
+
+func listUsers(*gin.Context) {}
+
+func getUser(*gin.Context) {}
+
+func TestCheckRoutes(t *testing.T) {
+	t.Parallel()
+
+	engine := gin.New()
+	engine.GET("/swagger/*any", func(*gin.Context) {})
+
+	api := engine.Group("/api/v1")
+	api.GET("/users", listUsers)
+	api.GET("/users/:name", getUser)
+	api.GET("/files/*filepath", func(*gin.Context) {})
+	api.POST("/users", listUsers)
+
+	err := CheckRoutes(engine, routeInfos, IgnoreRoutes("/swagger/*any"))
+
+	var drift *RouteDriftError
+	assert.True(t, errors.As(err, &drift))
+	assert.Equal(t, []Route{{Method: "DELETE", Path: "/api/v1/users/:id", Handler: "api.DeleteUser"}}, drift.Unregistered)
+	assert.Equal(t, []Route{{Method: "POST", Path: "/api/v1/users", Handler: "github.com/CloverOS/swag-gin/contract.listUsers"}}, drift.Undocumented)
+	assert.Equal(t, "the docs and the router differ by 2 routes:\n"+
+		"\tdocumented but not registered: DELETE /api/v1/users/:id (api.DeleteUser)\n"+
+		"\tregistered but not documented: POST /api/v1/users (github.com/CloverOS/swag-gin/contract.listUsers)", err.Error())
+
+	api.DELETE("/users/:id", getUser)
+	assert.EqualError(t, CheckRoutes(engine, routeInfos, IgnoreRoutes("/swagger/*any", "/api/v1/users")),
+		"the docs and the router differ by 1 routes:\n\tdocumented but not registered: GET /api/v1/users (api.ListUsers)")
+
+	engine = gin.New()
+	engine.GET("/v2/users", listUsers)
+	assert.NoError(t, CheckRoutes(engine, routeInfos[:1], SetBasePath("/v2")))
+	assert.NoError(t, CheckRoutes(gin.New(), []RouteInfos{}))
+
+	assert.Error(t, CheckRoutes(engine, "routes"))
+}
+
+func TestJoinPaths(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/", joinPaths("", "/"))
+	assert.Equal(t, "/", joinPaths("/", ""))
+	assert.Equal(t, "/api", joinPaths("/api/", ""))
+	assert.Equal(t, "/api/", joinPaths("/api", "/"))
+	assert.Equal(t, "/api/users/", joinPaths("/api/", "/users/"))
+	assert.Equal(t, "/users", joinPaths("", "/users"))
+}
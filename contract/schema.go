@@ -0,0 +1,392 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/go-openapi/spec"
+)
+
+// schemaValidator validates decoded JSON values against the schemas of a spec.
+type schemaValidator struct {
+	definitions spec.Definitions
+
+	// undeclared whether properties missing in a schema without additionalProperties are violations
+	undeclared bool
+}
+
+// patterns caches the compiled pattern constraints of the spec.
+var patterns sync.Map
+
+// validate returns the violations of value, decoded with json.Decoder.UseNumber, against schema at field.
+// A null is accepted for any schema, as Go encodes nil slices, maps and pointers as null.
+func (v schemaValidator) validate(schema *spec.Schema, value interface{}, field string) []Violation {
+	if schema == nil || value == nil {
+		return nil
+	}
+
+	schema = v.resolve(schema)
+	if schema == nil {
+		return nil
+	}
+
+	if alternatives := oneOf(schema); len(alternatives) > 0 {
+		return v.validateOneOf(alternatives, value, field)
+	}
+
+	if len(schema.AllOf) > 0 {
+		return v.validateObject(schema, value, field)
+	}
+
+	var violations []Violation
+
+	typ := schemaType(schema)
+	if typ != "" && !matchesType(typ, value) {
+		return []Violation{{Field: field, Message: fmt.Sprintf("must be %s, got %s", article(typ), jsonType(value))}}
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		violations = append(violations, Violation{Field: field, Message: "must be one of " + enumString(schema.Enum)})
+	}
+
+	switch value := value.(type) {
+	case string:
+		violations = append(violations, validateString(value, field, schema.MinLength, schema.MaxLength, schema.Pattern)...)
+	case json.Number:
+		number, _ := value.Float64()
+		validations := schema.Validations()
+		violations = append(violations, validateNumber(number, field, &validations.CommonValidations)...)
+	case []interface{}:
+		violations = append(violations, validateItems(len(value), field, schema.MinItems, schema.MaxItems)...)
+		if schema.UniqueItems && !uniqueItems(value) {
+			violations = append(violations, Violation{Field: field, Message: "must not contain duplicate items"})
+		}
+
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for i, item := range value {
+				violations = append(violations, v.validate(schema.Items.Schema, item, fmt.Sprintf("%s[%d]", field, i))...)
+			}
+		}
+	case map[string]interface{}:
+		violations = append(violations, v.validateObject(schema, value, field)...)
+	}
+
+	return violations
+}
+
+// oneOfExtension lists the alternatives of a Type1|Type2 schema in the Swagger 2.0 outputs, which have no oneOf.
+const oneOfExtension = "x-one-of"
+
+// oneOf returns the alternatives of schema, from oneOf or else from the x-one-of extension.
+func oneOf(schema *spec.Schema) []spec.Schema {
+	if len(schema.OneOf) > 0 {
+		return schema.OneOf
+	}
+
+	extension, ok := schema.Extensions[oneOfExtension]
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(extension)
+	if err != nil {
+		return nil
+	}
+
+	var alternatives []spec.Schema
+	if json.Unmarshal(data, &alternatives) != nil {
+		return nil
+	}
+
+	return alternatives
+}
+
+// validateOneOf accepts a value matching any of the alternatives. A value matching several is not a violation,
+// as the objects of a Type1|Type2 response usually satisfy both schemas.
+func (v schemaValidator) validateOneOf(alternatives []spec.Schema, value interface{}, field string) []Violation {
+	for i := range alternatives {
+		if len(v.validate(&alternatives[i], value, field)) == 0 {
+			return nil
+		}
+	}
+
+	return []Violation{{Field: field, Message: fmt.Sprintf("must match one of the %d alternatives", len(alternatives))}}
+}
+
+// validateObject validates an object against the properties of schema merged with those of its allOf
+// members, a later member overriding a property like swag does for model.Page{data=[]model.User}.
+func (v schemaValidator) validateObject(schema *spec.Schema, value interface{}, field string) []Violation {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return []Violation{{Field: field, Message: "must be an object, got " + jsonType(value)}}
+	}
+
+	var (
+		violations []Violation
+		properties = make(map[string]spec.Schema)
+		required   []string
+		additional *spec.SchemaOrBool
+		hasProps   bool
+	)
+
+	var merge func(schema *spec.Schema)
+	merge = func(schema *spec.Schema) {
+		schema = v.resolve(schema)
+		if schema == nil {
+			return
+		}
+
+		for i := range schema.AllOf {
+			merge(&schema.AllOf[i])
+		}
+
+		for name, property := range schema.Properties {
+			properties[name] = property
+			hasProps = true
+		}
+
+		required = append(required, schema.Required...)
+		if schema.AdditionalProperties != nil {
+			additional = schema.AdditionalProperties
+		}
+	}
+	merge(schema)
+
+	sort.Strings(required)
+	for i, name := range required {
+		if i > 0 && required[i-1] == name {
+			continue
+		}
+
+		if _, ok := object[name]; !ok {
+			violations = append(violations, Violation{Field: joinField(field, name), Message: "is required"})
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := properties[name]
+		if ok {
+			violations = append(violations, v.validate(&property, object[name], joinField(field, name))...)
+
+			continue
+		}
+
+		switch {
+		case additional != nil && additional.Schema != nil:
+			violations = append(violations, v.validate(additional.Schema, object[name], joinField(field, name))...)
+		case additional != nil && !additional.Allows:
+			violations = append(violations, Violation{Field: joinField(field, name), Message: "is not a documented property"})
+		case additional == nil && hasProps && v.undeclared:
+			violations = append(violations, Violation{Field: joinField(field, name), Message: "is not a documented property"})
+		}
+	}
+
+	return violations
+}
+
+// resolve follows the #/definitions reference of schema, nil when the definition is missing.
+func (v schemaValidator) resolve(schema *spec.Schema) *spec.Schema {
+	for seen := 0; schema != nil && schema.Ref.String() != ""; seen++ {
+		if seen > len(v.definitions) {
+			return nil
+		}
+
+		definition, ok := v.definitions[strings.TrimPrefix(schema.Ref.String(), "#/definitions/")]
+		if !ok {
+			return nil
+		}
+
+		schema = &definition
+	}
+
+	return schema
+}
+
+func schemaType(schema *spec.Schema) string {
+	if len(schema.Type) == 1 {
+		return schema.Type[0]
+	}
+
+	if len(schema.Type) == 0 && len(schema.Properties) > 0 {
+		return "object"
+	}
+
+	return ""
+}
+
+// matchesType reports whether a decoded JSON value is of the JSON schema type typ.
+func matchesType(typ string, value interface{}) bool {
+	switch value := value.(type) {
+	case string:
+		return typ == "string"
+	case bool:
+		return typ == "boolean"
+	case json.Number:
+		if typ == "number" {
+			return true
+		}
+
+		_, err := value.Int64()
+
+		return typ == "integer" && err == nil
+	case []interface{}:
+		return typ == "array"
+	case map[string]interface{}:
+		return typ == "object"
+	}
+
+	return false
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case json.Number:
+		return "a number"
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	}
+
+	return "null"
+}
+
+func article(typ string) string {
+	switch typ {
+	case "array", "integer", "object":
+		return "an " + typ
+	}
+
+	return "a " + typ
+}
+
+// inEnum reports whether value, a decoded JSON value or a converted parameter, is one of enum.
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func enumString(enum []interface{}) string {
+	values := make([]string, 0, len(enum))
+	for _, value := range enum {
+		values = append(values, fmt.Sprint(value))
+	}
+
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+func validateString(value, field string, minLength, maxLength *int64, pattern string) []Violation {
+	var violations []Violation
+
+	length := int64(utf8.RuneCountInString(value))
+	if minLength != nil && length < *minLength {
+		violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("must be at least %d characters long", *minLength)})
+	}
+
+	if maxLength != nil && length > *maxLength {
+		violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("must be at most %d characters long", *maxLength)})
+	}
+
+	if pattern != "" {
+		compiled, ok := patterns.Load(pattern)
+		if !ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return violations
+			}
+
+			compiled, _ = patterns.LoadOrStore(pattern, re)
+		}
+
+		if !compiled.(*regexp.Regexp).MatchString(value) {
+			violations = append(violations, Violation{Field: field, Message: "must match the pattern " + pattern})
+		}
+	}
+
+	return violations
+}
+
+func validateNumber(value float64, field string, validations *spec.CommonValidations) []Violation {
+	var violations []Violation
+
+	if validations.Minimum != nil {
+		if validations.ExclusiveMinimum && value <= *validations.Minimum {
+			violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("must be greater than %v", *validations.Minimum)})
+		} else if value < *validations.Minimum {
+			violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("must be greater than or equal to %v", *validations.Minimum)})
+		}
+	}
+
+	if validations.Maximum != nil {
+		if validations.ExclusiveMaximum && value >= *validations.Maximum {
+			violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("must be less than %v", *validations.Maximum)})
+		} else if value > *validations.Maximum {
+			violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("must be less than or equal to %v", *validations.Maximum)})
+		}
+	}
+
+	if validations.MultipleOf != nil && *validations.MultipleOf != 0 {
+		if quotient := value / *validations.MultipleOf; quotient != math.Trunc(quotient) {
+			violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("must be a multiple of %v", *validations.MultipleOf)})
+		}
+	}
+
+	return violations
+}
+
+func validateItems(count int, field string, minItems, maxItems *int64) []Violation {
+	var violations []Violation
+
+	if minItems != nil && int64(count) < *minItems {
+		violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("must have at least %d items", *minItems)})
+	}
+
+	if maxItems != nil && int64(count) > *maxItems {
+		violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("must have at most %d items", *maxItems)})
+	}
+
+	return violations
+}
+
+func uniqueItems(items []interface{}) bool {
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		key, _ := json.Marshal(item)
+		if seen[string(key)] {
+			return false
+		}
+
+		seen[string(key)] = true
+	}
+
+	return true
+}
+
+func joinField(field, name string) string {
+	if field == "" {
+		return name
+	}
+
+	return field + "." + name
+}
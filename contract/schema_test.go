@@ -0,0 +1,109 @@
+package contract
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaValidator(t *testing.T) {
+	t.Parallel()
+
+	var swagger spec.Swagger
+	assert.NoError(t, json.Unmarshal([]byte(testDoc), &swagger))
+
+	// model.Page{data=[]model.User} as generated by swag
+	page := spec.Schema{SchemaProps: spec.SchemaProps{AllOf: []spec.Schema{
+		{SchemaProps: spec.SchemaProps{
+			Type:       []string{"object"},
+			Properties: map[string]spec.Schema{"data": *spec.StringProperty(), "total": *spec.Int64Property()},
+		}},
+		{SchemaProps: spec.SchemaProps{Properties: map[string]spec.Schema{
+			"data": *spec.ArrayProperty(spec.RefSchema("#/definitions/model.User")),
+		}}},
+	}}}
+
+	// model.User|model.Error, as generated for the openapi3 output type and for swagger.json
+	userOrError := spec.Schema{SchemaProps: spec.SchemaProps{OneOf: []spec.Schema{
+		*spec.RefSchema("#/definitions/model.User"), *spec.RefSchema("#/definitions/model.Error"),
+	}}}
+	userOrErrorExtension := spec.Schema{}
+	userOrErrorExtension.AddExtension(oneOfExtension, []interface{}{
+		map[string]interface{}{"$ref": "#/definitions/model.User"}, map[string]interface{}{"type": "string"},
+	})
+
+	tests := []struct {
+		name       string
+		schema     *spec.Schema
+		value      string
+		undeclared bool
+		violations []Violation
+	}{
+		{name: "ref", schema: spec.RefSchema("#/definitions/model.User"), value: `{"id": 1, "name": "gopher"}`},
+		{name: "null", schema: spec.RefSchema("#/definitions/model.User"), value: `null`},
+		{name: "missing ref", schema: spec.RefSchema("#/definitions/model.Missing"), value: `1`},
+		{
+			name: "type", schema: spec.RefSchema("#/definitions/model.User"), value: `[]`,
+			violations: []Violation{{Message: "must be an object, got an array"}},
+		},
+		{
+			name: "undeclared ignored", schema: spec.RefSchema("#/definitions/model.User"), value: `{"id": 1, "email": "x"}`,
+		},
+		{
+			name: "undeclared", schema: spec.RefSchema("#/definitions/model.User"), value: `{"id": 1, "email": "x"}`, undeclared: true,
+			violations: []Violation{{Field: "email", Message: "is not a documented property"}},
+		},
+		{
+			name: "required", schema: spec.RefSchema("#/definitions/model.CreateUser"), value: `{}`,
+			violations: []Violation{{Field: "name", Message: "is required"}},
+		},
+		{
+			name: "allOf override", schema: &page, value: `{"data": [{"id": "1"}], "total": 1, "next": 2}`, undeclared: true,
+			violations: []Violation{
+				{Field: "data[0].id", Message: "must be an integer, got a string"},
+				{Field: "next", Message: "is not a documented property"},
+			},
+		},
+		{
+			name: "map", schema: spec.MapProperty(spec.Int64Property()), value: `{"a": 1, "b": "2"}`, undeclared: true,
+			violations: []Violation{{Field: "b", Message: "must be an integer, got a string"}},
+		},
+		{name: "oneOf", schema: &userOrError, value: `{"error": "not found"}`, undeclared: true},
+		{
+			name: "oneOf none", schema: &userOrError, value: `{"id": "1"}`, undeclared: true,
+			violations: []Violation{{Message: "must match one of the 2 alternatives"}},
+		},
+		{name: "oneOf extension", schema: &userOrErrorExtension, value: `"gopher"`},
+		{
+			name: "oneOf extension none", schema: &userOrErrorExtension, value: `1`,
+			violations: []Violation{{Message: "must match one of the 2 alternatives"}},
+		},
+		{
+			name: "unique", schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"array"}, UniqueItems: true}}, value: `[1, 1]`,
+			violations: []Violation{{Message: "must not contain duplicate items"}},
+		},
+	}
+	for _, test := range tests {
+		value, err := decodeJSON([]byte(test.value))
+		assert.NoError(t, err, test.name)
+
+		validator := schemaValidator{definitions: swagger.Definitions, undeclared: test.undeclared}
+		assert.Equal(t, test.violations, validator.validate(test.schema, value, ""), test.name)
+	}
+}
+
+func TestValidateNumber(t *testing.T) {
+	t.Parallel()
+
+	minimum, maximum, multipleOf := 1.0, 10.0, 0.5
+	validations := &spec.CommonValidations{
+		Minimum: &minimum, ExclusiveMinimum: true, Maximum: &maximum, MultipleOf: &multipleOf,
+	}
+
+	assert.Empty(t, validateNumber(2.5, "n", validations))
+	assert.Equal(t, []Violation{{Field: "n", Message: "must be greater than 1"}}, validateNumber(1, "n", validations))
+	assert.Equal(t, []Violation{{Field: "n", Message: "must be less than or equal to 10"}}, validateNumber(10.5, "n", validations))
+	assert.Equal(t, []Violation{{Field: "n", Message: "must be a multiple of 0.5"}}, validateNumber(2.2, "n", validations))
+}
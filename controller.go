@@ -0,0 +1,161 @@
+package swag
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"unicode"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// HandlerReceiver is the receiver type of a method handler, e.g. *Controller.
+type HandlerReceiver struct {
+	// Type is the name of the receiver type
+	Type string
+
+	// Pointer whether the method has a pointer receiver
+	Pointer bool
+}
+
+// String returns the receiver as written in a method expression, e.g. (*Controller).
+func (recv HandlerReceiver) String() string {
+	if recv.Pointer {
+		return "(*" + recv.Type + ")"
+	}
+
+	return recv.Type
+}
+
+// parseHandlerReceiver returns the receiver of a method declaration, generic receivers are not supported.
+func parseHandlerReceiver(recv *ast.FieldList) (HandlerReceiver, bool) {
+	if recv == nil || len(recv.List) == 0 {
+		return HandlerReceiver{}, false
+	}
+
+	expr := recv.List[0].Type
+	star, pointer := expr.(*ast.StarExpr)
+	if pointer {
+		expr = star.X
+	}
+
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return HandlerReceiver{}, false
+	}
+
+	return HandlerReceiver{Type: ident.Name, Pointer: pointer}, true
+}
+
+// controllerParam is a controller instance or factory dependency a generated router function takes as a parameter.
+type controllerParam struct {
+	pkg     string // import path of the receiver type
+	typ     string
+	pointer bool
+	name    string
+}
+
+// controllerParams returns the controllers the method handlers of infos are called on and the
+// dependencies their factories are called with, sorted by package and type, and named after their
+// type unless that name is taken.
+func controllerParams(infos []RouteInfos) []controllerParam {
+	index := make(map[[2]string]int)
+	reserved := map[string]bool{"r": true, "g": true}
+	var params []controllerParam
+	add := func(pkg, typ string, pointer bool) {
+		reserved[importPackageName(pkg)] = true
+		key := [2]string{pkg, typ}
+		if i, ok := index[key]; ok {
+			params[i].pointer = params[i].pointer || pointer
+			return
+		}
+		index[key] = len(params)
+		params = append(params, controllerParam{pkg: pkg, typ: typ, pointer: pointer})
+	}
+	for _, info := range infos {
+		reserved[importPackageName(info.handlerPkg)] = true
+		for _, middleware := range info.Middlewares {
+			reserved[middleware.Package] = true
+		}
+		if info.signature.Receiver.Type != "" {
+			add(info.handlerPkg, info.signature.Receiver.Type, info.signature.Receiver.Pointer)
+		}
+		for _, dep := range info.signature.Args {
+			add(dep.importPath(info.handlerPkg), dep.Type, dep.Pointer)
+		}
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].pkg != params[j].pkg {
+			return params[i].pkg < params[j].pkg
+		}
+		return params[i].typ < params[j].typ
+	})
+
+	for i := range params {
+		candidates := []string{
+			unexportedIdent(params[i].typ),
+			unexportedIdent(exportedIdent(importPackageName(params[i].pkg)) + params[i].typ),
+		}
+		name := candidates[len(candidates)-1]
+		for _, candidate := range candidates {
+			if !reserved[candidate] && !token.IsKeyword(candidate) {
+				name = candidate
+				break
+			}
+		}
+		for n := 2; reserved[name] || token.IsKeyword(name); n++ {
+			name = candidates[len(candidates)-1] + strconv.Itoa(n)
+		}
+		reserved[name] = true
+		params[i].name = name
+	}
+	return params
+}
+
+// controllerName returns the parameter name of the controller of type typ in pkg.
+func controllerName(params []controllerParam, pkg, typ string) string {
+	for _, param := range params {
+		if param.pkg == pkg && param.typ == typ {
+			return param.name
+		}
+	}
+	return ""
+}
+
+// routerParams declares the router parameter followed by the controllers.
+func routerParams(backend RouterBackend, params []controllerParam) []jen.Code {
+	code := []jen.Code{backend.RouterParam()}
+	for _, param := range params {
+		typ := jen.Id(param.name)
+		if param.pointer {
+			typ = typ.Op("*")
+		}
+		code = append(code, typ.Qual(param.pkg, param.typ))
+	}
+	return code
+}
+
+// routerArgs passes the router and the controllers on to another router function.
+func routerArgs(params []controllerParam) []jen.Code {
+	code := []jen.Code{jen.Id("r")}
+	for _, param := range params {
+		code = append(code, jen.Id(param.name))
+	}
+	return code
+}
+
+// unexportedIdent lowers the leading upper case letters of name, e.g. HTTPController becomes httpController.
+func unexportedIdent(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,73 @@
+package swag
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHandlerReceiver(t *testing.T) {
+	t.Parallel()
+
+	src := `package api
+
+func (c *Controller) ShowAccount() {}
+
+func (c Controller) ListAccounts() {}
+
+func (l List[T]) Generic() {}
+
+func Plain() {}
+`
+	astFile, err := goparser.ParseFile(token.NewFileSet(), "api.go", src, 0)
+	require.NoError(t, err)
+
+	expected := []struct {
+		recv HandlerReceiver
+		ok   bool
+	}{
+		{HandlerReceiver{Type: "Controller", Pointer: true}, true},
+		{HandlerReceiver{Type: "Controller"}, true},
+		{HandlerReceiver{}, false},
+		{HandlerReceiver{}, false},
+	}
+	for i, decl := range astFile.Decls {
+		recv, ok := parseHandlerReceiver(decl.(*ast.FuncDecl).Recv)
+		assert.Equal(t, expected[i].ok, ok)
+		assert.Equal(t, expected[i].recv, recv)
+	}
+
+	assert.Equal(t, "(*Controller)", HandlerReceiver{Type: "Controller", Pointer: true}.String())
+	assert.Equal(t, "Controller", HandlerReceiver{Type: "Controller"}.String())
+}
+
+func TestControllerParams(t *testing.T) {
+	t.Parallel()
+
+	infos := []RouteInfos{
+		{handlerPkg: "example.com/users", signature: HandlerSignature{Receiver: HandlerReceiver{Type: "Controller"}}},
+		{handlerPkg: "example.com/users", signature: HandlerSignature{Receiver: HandlerReceiver{Type: "Controller", Pointer: true}}},
+		{handlerPkg: "example.com/accounts", signature: HandlerSignature{Receiver: HandlerReceiver{Type: "Controller", Pointer: true}}},
+		{handlerPkg: "example.com/api", signature: HandlerSignature{Receiver: HandlerReceiver{Type: "API"}}},
+		{handlerPkg: "example.com/api"},
+	}
+
+	assert.Equal(t, []controllerParam{
+		{pkg: "example.com/accounts", typ: "Controller", pointer: true, name: "controller"},
+		{pkg: "example.com/api", typ: "API", name: "apiAPI"},
+		{pkg: "example.com/users", typ: "Controller", pointer: true, name: "usersController"},
+	}, controllerParams(infos))
+}
+
+func TestUnexportedIdent(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "controller", unexportedIdent("Controller"))
+	assert.Equal(t, "httpController", unexportedIdent("HTTPController"))
+	assert.Equal(t, "api", unexportedIdent("API"))
+	assert.Equal(t, "x", unexportedIdent("x"))
+}
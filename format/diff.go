@@ -0,0 +1,175 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around the changes of a hunk.
+const diffContext = 3
+
+type diffLine struct {
+	// kind is ' ' for an unchanged line, '-' for a removed line and '+' for an added line
+	kind byte
+	text string
+
+	// before and after are the indexes of the line in the old and the new contents
+	before int
+	after  int
+}
+
+// unifiedDiff returns the changes from before to after as a unified diff, empty when they are equal.
+func unifiedDiff(name string, before, after []byte) string {
+	lines := diffLines(splitLines(string(before)), splitLines(string(after)))
+
+	var hunks [][2]int
+	for i, line := range lines {
+		if line.kind == ' ' {
+			continue
+		}
+
+		start, end := i-diffContext, i+diffContext+1
+		if start < 0 {
+			start = 0
+		}
+
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1][1] {
+			hunks[len(hunks)-1][1] = end
+		} else {
+			hunks = append(hunks, [2]int{start, end})
+		}
+	}
+
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	diff := &strings.Builder{}
+	_, _ = fmt.Fprintf(diff, "--- %s.orig\n+++ %s\n", name, name)
+
+	for _, hunk := range hunks {
+		var beforeCount, afterCount int
+		for _, line := range lines[hunk[0]:hunk[1]] {
+			if line.kind != '+' {
+				beforeCount++
+			}
+
+			if line.kind != '-' {
+				afterCount++
+			}
+		}
+
+		first := lines[hunk[0]]
+		_, _ = fmt.Fprintf(diff, "@@ -%s +%s @@\n", hunkRange(first.before, beforeCount), hunkRange(first.after, afterCount))
+
+		for _, line := range lines[hunk[0]:hunk[1]] {
+			diff.WriteByte(line.kind)
+			diff.WriteString(line.text)
+			diff.WriteByte('\n')
+		}
+	}
+
+	return diff.String()
+}
+
+// hunkRange returns the start,count of a hunk starting at the line index start, the line before an empty hunk.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitLines(contents string) []string {
+	if contents == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(contents, "\n"), "\n")
+}
+
+// diffLines returns the shortest edit script from before to after, found with the Myers algorithm.
+func diffLines(before, after []string) []diffLine {
+	n, m := len(before), len(after)
+	offset := n + m
+	v := make([]int, 2*offset+2)
+
+	// trace[d] is v before step d, to walk the edits back from the end
+	var trace [][]int
+
+	for d := 0; d <= offset; d++ {
+		trace = append(trace, append([]int(nil), v...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && before[x] == after[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, offset, before, after)
+			}
+		}
+	}
+
+	return nil
+}
+
+func backtrack(trace [][]int, offset int, before, after []string) []diffLine {
+	var lines []diffLine
+
+	x, y := len(before), len(after)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			lines = append(lines, diffLine{kind: ' ', text: before[x], before: x, after: y})
+		}
+
+		if d == 0 {
+			break
+		}
+
+		if x == prevX {
+			y--
+			lines = append(lines, diffLine{kind: '+', text: after[y], before: x, after: y})
+		} else {
+			x--
+			lines = append(lines, diffLine{kind: '-', text: before[x], before: x, after: y})
+		}
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+
+	return lines
+}
@@ -0,0 +1,19 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	assert.Empty(t, unifiedDiff("same.go", []byte("a\nb\n"), []byte("a\nb\n")))
+
+	before := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n")
+	after := []byte("1\n2\n3\nfour\n5\n6\n7\n8\n9\n10\n11\n12\n13\n")
+	assert.Equal(t, "--- a.go.orig\n+++ a.go\n"+
+		"@@ -1,7 +1,7 @@\n 1\n 2\n 3\n-4\n+four\n 5\n 6\n 7\n"+
+		"@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n", unifiedDiff("a.go", before, after))
+
+	assert.Equal(t, "--- b.go.orig\n+++ b.go\n@@ -0,0 +1,1 @@\n+package b\n", unifiedDiff("b.go", nil, []byte("package b\n")))
+}
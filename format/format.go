@@ -1,14 +1,22 @@
 package format
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/swaggo/swag"
+	"github.com/CloverOS/swag-gin"
 )
 
+// Stdin is the SearchDir formatting the Go source read from Config.Input to Config.Output.
+const Stdin = "-"
+
+// stdinName names the standard input in the diffs and the list of unformatted files.
+const stdinName = "<standard input>"
+
 // Format implements `fmt` command for formatting swag comments in Go source
 // files.
 type Format struct {
@@ -16,6 +24,13 @@ type Format struct {
 
 	// exclude exclude dirs and files in SearchDir
 	exclude map[string]bool
+
+	check  bool
+	diff   bool
+	output io.Writer
+
+	// unformatted are the files whose swag comments are not formatted, in check mode
+	unformatted []string
 }
 
 // New creates a new Format instance
@@ -36,12 +51,42 @@ type Config struct {
 
 	// MainFile (DEPRECATED)
 	MainFile string
+
+	// Check lists the files whose swag comments are not formatted and fails, without writing them
+	Check bool
+
+	// Diff prints the formatting changes as unified diffs, without writing the files
+	Diff bool
+
+	// Input is read when SearchDir is Stdin, os.Stdin by default
+	Input io.Reader
+
+	// Output receives the formatted standard input, the unformatted files and the diffs, os.Stdout by default
+	Output io.Writer
 }
 
 var defaultExcludes = []string{"docs", "vendor"}
 
 // Build runs formatter according to configuration in config
 func (f *Format) Build(config *Config) error {
+	f.check, f.diff, f.output = config.Check, config.Diff, config.Output
+	if f.output == nil {
+		f.output = os.Stdout
+	}
+
+	if config.SearchDir == Stdin {
+		input := config.Input
+		if input == nil {
+			input = os.Stdin
+		}
+
+		if err := f.formatStdin(input); err != nil {
+			return fmt.Errorf("fmt: %w", err)
+		}
+
+		return f.checkResult()
+	}
+
 	searchDirs := strings.Split(config.SearchDir, ",")
 	for _, searchDir := range searchDirs {
 		if _, err := os.Stat(searchDir); os.IsNotExist(err) {
@@ -62,9 +107,59 @@ func (f *Format) Build(config *Config) error {
 			return err
 		}
 	}
+	return f.checkResult()
+}
+
+// Run formats the swag comments of the Go source read from src and writes it to dst.
+func (f *Format) Run(src io.Reader, dst io.Writer) error {
+	contents, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+	formatted, err := f.formatter.Format(stdinName, contents)
+	if err != nil {
+		return err
+	}
+	_, err = dst.Write(formatted)
+	return err
+}
+
+func (f *Format) checkResult() error {
+	if f.check && len(f.unformatted) > 0 {
+		return fmt.Errorf("fmt: the swag comments of %d files are not formatted", len(f.unformatted))
+	}
 	return nil
 }
 
+func (f *Format) formatStdin(input io.Reader) error {
+	if !f.check && !f.diff {
+		return f.Run(input, f.output)
+	}
+	contents, err := io.ReadAll(input)
+	if err != nil {
+		return err
+	}
+	formatted, err := f.formatter.Format(stdinName, contents)
+	if err != nil {
+		return err
+	}
+	return f.report(stdinName, contents, formatted)
+}
+
+// report records and prints an unformatted file in check or diff mode.
+func (f *Format) report(path string, contents, formatted []byte) error {
+	if bytes.Equal(contents, formatted) {
+		return nil
+	}
+	f.unformatted = append(f.unformatted, path)
+	if f.diff {
+		_, err := io.WriteString(f.output, unifiedDiff(path, contents, formatted))
+		return err
+	}
+	_, err := fmt.Fprintln(f.output, path)
+	return err
+}
+
 func (f *Format) visit(path string, fileInfo os.FileInfo, err error) error {
 	if fileInfo.IsDir() && f.excludeDir(path) {
 		return filepath.SkipDir
@@ -98,6 +193,12 @@ func (f *Format) format(path string) error {
 	if err != nil {
 		return err
 	}
+	if f.check || f.diff {
+		return f.report(path, contents, formatted)
+	}
+	if bytes.Equal(contents, formatted) {
+		return nil
+	}
 	return write(path, formatted)
 }
 
@@ -61,6 +61,63 @@ func TestFormat_WriteError(t *testing.T) {
 	os.Chmod(fx.basedir, 0755)
 }
 
+func TestFormat_Check(t *testing.T) {
+	fx := setup(t)
+	output := &bytes.Buffer{}
+	assert.EqualError(t, New().Build(&Config{SearchDir: fx.basedir, Check: true, Output: output}),
+		"fmt: the swag comments of 2 files are not formatted")
+	assert.Equal(t, filepath.Join(fx.basedir, "api/api.go")+"\n"+filepath.Join(fx.basedir, "main.go")+"\n", output.String())
+	assert.False(t, fx.isFormatted("api/api.go"))
+	assert.False(t, fx.isFormatted("main.go"))
+
+	assert.NoError(t, New().Build(&Config{SearchDir: fx.basedir}))
+	output.Reset()
+	assert.NoError(t, New().Build(&Config{SearchDir: fx.basedir, Check: true, Output: output}))
+	assert.Empty(t, output.String())
+}
+
+func TestFormat_Diff(t *testing.T) {
+	fx := setup(t)
+	output := &bytes.Buffer{}
+	assert.NoError(t, New().Build(&Config{SearchDir: fx.basedir, Excludes: filepath.Join(fx.basedir, "main.go"), Diff: true, Output: output}))
+	assert.False(t, fx.isFormatted("api/api.go"))
+
+	path := filepath.Join(fx.basedir, "api/api.go")
+	assert.Equal(t, "--- "+path+".orig\n+++ "+path+"\n"+
+		"@@ -2,8 +2,8 @@\n"+
+		" \n"+
+		" \t\timport \"net/http\"\n"+
+		" \n"+
+		"-\t\t// @Summary Add a new pet to the store\n"+
+		"-\t\t// @Description get string by ID\n"+
+		"+\t\t//\t@Summary\t\tAdd a new pet to the store\n"+
+		"+\t\t//\t@Description\tget string by ID\n"+
+		" \t\tfunc GetStringByInt(w http.ResponseWriter, r *http.Request) {\n"+
+		" \t\t\t//write your code\n"+
+		" \t\t}\n", output.String())
+}
+
+func TestFormat_Stdin(t *testing.T) {
+	output := &bytes.Buffer{}
+	assert.NoError(t, New().Build(&Config{
+		SearchDir: Stdin,
+		Input:     bytes.NewReader(testFiles["api/api.go"]),
+		Output:    output,
+	}))
+	assert.Contains(t, output.String(), "//\t@Summary\t\tAdd a new pet to the store\n")
+
+	formatted := output.Bytes()
+	output = &bytes.Buffer{}
+	assert.NoError(t, New().Build(&Config{SearchDir: Stdin, Input: bytes.NewReader(formatted), Check: true, Output: output}))
+	assert.Empty(t, output.String())
+
+	assert.EqualError(t, New().Build(&Config{SearchDir: Stdin, Input: bytes.NewReader(testFiles["api/api.go"]), Check: true, Output: output}),
+		"fmt: the swag comments of 1 files are not formatted")
+	assert.Equal(t, "<standard input>\n", output.String())
+
+	assert.Error(t, New().Build(&Config{SearchDir: Stdin, Input: bytes.NewReader([]byte("package main\nfunc invalid() {")), Output: output}))
+}
+
 func TestFormat_InvalidSearchDir(t *testing.T) {
 	formatter := New()
 	assert.Error(t, formatter.Build(&Config{SearchDir: "no_such_dir"}))
@@ -23,6 +23,36 @@ var specialTagForSplit = map[string]bool{
 	headerAttr:   true,
 }
 
+// alignGroup aligns the columns of each kind of @Param @Success @Failure @Response @Header on their own.
+var alignGroup = map[string]string{
+	paramAttr:    paramAttr,
+	successAttr:  responseAttr,
+	failureAttr:  responseAttr,
+	responseAttr: responseAttr,
+	headerAttr:   headerAttr,
+}
+
+// attributeOrder is the canonical order of the attributes of an operation, followed by the other attributes
+// and @Router.
+var attributeOrder = []string{
+	summaryAttr,
+	descriptionAttr,
+	descriptionMarkdownAttr,
+	tagsAttr,
+	idAttr,
+	acceptAttr,
+	produceAttr,
+	paramAttr,
+	securityAttr,
+	middlewareAttr,
+	successAttr,
+	failureAttr,
+	responseAttr,
+	headerAttr,
+	deprecatedAttr,
+	xCodeSamplesAttr,
+}
+
 var skipChar = map[byte]byte{
 	'"': '"',
 	'(': ')',
@@ -81,6 +111,9 @@ type edit struct {
 type edits []edit
 
 func (edits edits) apply(contents []byte) []byte {
+	// The edits are applied to a copy, contents belongs to the caller.
+	contents = append([]byte(nil), contents...)
+
 	// Apply the edits with the highest offset first, so that earlier edits
 	// don't affect the offsets of later edits.
 	sort.Slice(edits, func(i, j int) bool {
@@ -96,6 +129,15 @@ func (edits edits) apply(contents []byte) []byte {
 	return contents
 }
 
+// escape passes the text it encloses through the tab writer as it is.
+var escape = string([]byte{tabwriter.Escape})
+
+// swagLine is a Swag attribute comment line of a comment block.
+type swagLine struct {
+	attr string
+	body string
+}
+
 // formatFuncDoc reformats the comment lines in commentList, and appends any
 // changes to the edit list.
 func formatFuncDoc(fileSet *token.FileSet, commentList []*ast.Comment, edits *edits) {
@@ -104,21 +146,43 @@ func formatFuncDoc(fileSet *token.FileSet, commentList []*ast.Comment, edits *ed
 	// each one we find, we replace alignment whitespace with a tab character,
 	// then write the result into a tab writer.
 
-	linesToComments := make(map[int]int, len(commentList))
+	var (
+		lines          []swagLine
+		commentIndexes []int
+		isOperation    bool
+	)
+
+	for commentIndex, comment := range commentList {
+		if attr, body, found := swagComment(comment.Text); found {
+			lines = append(lines, swagLine{attr: attr, body: body})
+			commentIndexes = append(commentIndexes, commentIndex)
+			isOperation = isOperation || strings.ToLower(attr) == routerAttr
+		}
+	}
+
+	// The attributes of an operation are sorted in the canonical order, the
+	// sorted lines take the places of the attribute lines of the block, so
+	// that the other comment lines stay where they are.
+	if isOperation {
+		sort.SliceStable(lines, func(i, j int) bool {
+			return attributeRank(lines[i].attr) < attributeRank(lines[j].attr)
+		})
+	}
+
+	bodies := alignBodies(lines)
 
 	buffer := &bytes.Buffer{}
-	w := tabwriter.NewWriter(buffer, 1, 4, 1, '\t', 0)
+	w := tabwriter.NewWriter(buffer, 1, 4, 1, '\t', tabwriter.StripEscape)
 
-	for commentIndex, comment := range commentList {
-		text := comment.Text
-		if attr, body, found := swagComment(text); found {
-			formatted := "//\t" + attr
-			if body != "" {
-				formatted += "\t" + splitComment2(attr, body)
-			}
-			_, _ = fmt.Fprintln(w, formatted)
-			linesToComments[len(linesToComments)] = commentIndex
+	linesToComments := make(map[int]int, len(lines))
+	for lineIndex, line := range lines {
+		formatted := "//\t" + line.attr
+		if bodies[lineIndex] != "" {
+			// the body is escaped, its columns were aligned by alignBodies
+			formatted += "\t" + escape + bodies[lineIndex] + escape
 		}
+		_, _ = fmt.Fprintln(w, formatted)
+		linesToComments[lineIndex] = commentIndexes[lineIndex]
 	}
 
 	// Once we've loaded all of the comment lines to be aligned into the tab
@@ -141,6 +205,63 @@ func formatFuncDoc(fileSet *token.FileSet, commentList []*ast.Comment, edits *ed
 	}
 }
 
+// attributeRank returns the position of attr in the canonical order of the attributes of an operation.
+func attributeRank(attr string) int {
+	attr = strings.ToLower(attr)
+	for rank, orderedAttr := range attributeOrder {
+		if attr == orderedAttr {
+			return rank
+		}
+	}
+
+	if attr == routerAttr {
+		return len(attributeOrder) + 1
+	}
+
+	return len(attributeOrder)
+}
+
+// alignBodies returns the bodies of lines, the columns of the bodies of each alignGroup aligned with each other.
+// The bodies start at a tab stop, so the tab aligned columns stay aligned wherever the attribute column ends.
+func alignBodies(lines []swagLine) []string {
+	bodies := make([]string, len(lines))
+	groups := make(map[string][]int)
+
+	for lineIndex, line := range lines {
+		if line.body == "" {
+			continue
+		}
+
+		group, ok := alignGroup[strings.ToLower(line.attr)]
+		if !ok {
+			bodies[lineIndex] = line.body
+
+			continue
+		}
+
+		groups[group] = append(groups[group], lineIndex)
+	}
+
+	for _, lineIndexes := range groups {
+		buffer := &bytes.Buffer{}
+		w := tabwriter.NewWriter(buffer, 1, 4, 1, '\t', 0)
+
+		for _, lineIndex := range lineIndexes {
+			line := lines[lineIndex]
+			_, _ = fmt.Fprintln(w, strings.TrimPrefix(splitComment2(line.attr, line.body), "\t"))
+		}
+
+		_ = w.Flush()
+
+		aligned := strings.Split(buffer.String(), "\n")
+		for i, lineIndex := range lineIndexes {
+			bodies[lineIndex] = aligned[i]
+		}
+	}
+
+	return bodies
+}
+
 func splitComment2(attr, body string) string {
 	if specialTagForSplit[strings.ToLower(attr)] {
 		for i := 0; i < len(body); i++ {
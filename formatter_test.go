@@ -166,17 +166,55 @@ func Test_FormatApi(t *testing.T) {
 	//	@ID				get-string-by-int
 	//	@Accept			json
 	//	@Produce		json
-	//	@Param			some_id	path		int				true	"Some ID"	Format(int64)
-	//	@Param			some_id	body		web.Pet			true	"Some ID"
-	//	@Success		200		{string}	string			"ok"
-	//	@Failure		400		{object}	web.APIError	"We need ID!!"
-	//	@Failure		404		{object}	web.APIError	"Can not find ID"
+	//	@Param			some_id	path	int		true	"Some ID"	Format(int64)
+	//	@Param			some_id	body	web.Pet	true	"Some ID"
+	//	@Success		200	{string}	string			"ok"
+	//	@Failure		400	{object}	web.APIError	"We need ID!!"
+	//	@Failure		404	{object}	web.APIError	"Can not find ID"
 	//	@Router			/testapi/get-string-by-int/{some_id} [get]
 	func GetStringByInt(w http.ResponseWriter, r *http.Request) {}`
 
 	testFormat(t, "api.go", contents, want)
 }
 
+func Test_FormatAttributeOrder(t *testing.T) {
+	contents := `package api
+
+	// GetUser godoc
+	// @Router /users/{id} [get]
+	// @Success 200 {object} web.User
+	// @Param id path int true "User ID"
+	// @Tags users
+	// @Failure 404 {object} web.APIError "Can not find the user"
+	// @x-order 1
+	// @Param verbose query bool false "Verbose output"
+	// @Summary Get a user
+	func GetUser() {}
+
+	// @description Not an operation
+	// @title Keeps its place
+	func main() {}`
+
+	want := `package api
+
+	// GetUser godoc
+	//	@Summary	Get a user
+	//	@Tags		users
+	//	@Param		id		path	int		true	"User ID"
+	//	@Param		verbose	query	bool	false	"Verbose output"
+	//	@Success	200	{object}	web.User
+	//	@Failure	404	{object}	web.APIError	"Can not find the user"
+	//	@x-order	1
+	//	@Router		/users/{id} [get]
+	func GetUser() {}
+
+	//	@description	Not an operation
+	//	@title			Keeps its place
+	func main() {}`
+
+	testFormat(t, "order.go", contents, want)
+}
+
 func Test_NonSwagComment(t *testing.T) {
 	contents := `package api
 	// @Summary Add a new pet to the store
@@ -4,8 +4,10 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/CloverOS/swag-gin"
+	"github.com/CloverOS/swag-gin/openapi3"
 	"go/format"
 	"io"
 	"log"
@@ -25,6 +27,10 @@ var open = os.Open
 // DefaultOverridesFile is the location swagger will look for type overrides.
 const DefaultOverridesFile = ".swaggo"
 
+// ErrTemplateDelims rejects custom template delimiters: the generated docs.go registers a swag.Spec of
+// github.com/swaggo/swag v1.8.4, which has no LeftDelim and RightDelim before v1.16.1.
+var ErrTemplateDelims = errors.New("custom template delimiters are not supported, swag.Spec of github.com/swaggo/swag v1.8.4 has no LeftDelim and RightDelim")
+
 type genTypeWriter func(*Config, *spec.Swagger) error
 
 // Gen presents a generate tool for swag.
@@ -34,6 +40,7 @@ type Gen struct {
 	jsonToYAML    func(data []byte) ([]byte, error)
 	outputTypeMap map[string]genTypeWriter
 	debug         Debugger
+	writer        *swag.GenWriter
 }
 
 // Debugger is the interface that wraps the basic Printf method.
@@ -57,6 +64,8 @@ func New() *Gen {
 		"json": gen.writeJSONSwagger,
 		"yaml": gen.writeYAMLSwagger,
 		"yml":  gen.writeYAMLSwagger,
+
+		openAPI3OutputType: gen.writeOpenAPI3,
 	}
 
 	return &gen
@@ -109,6 +118,30 @@ type Config struct {
 	// Strict whether swag should error or warn when it detects cases which are most likely user errors
 	Strict bool
 
+	// Tags filters the operations by tag, comma separated, a tag prefixed with ! is excluded
+	Tags string
+
+	// ParseExtension parses only the operations declaring this x- extension
+	ParseExtension string
+
+	// LeftTemplateDelim is not supported, Build returns ErrTemplateDelims when it is set
+	LeftTemplateDelim string
+
+	// RightTemplateDelim is not supported, Build returns ErrTemplateDelims when it is set
+	RightTemplateDelim string
+
+	// PackageName is the package name of docs.go and resource.go, defaults to the name of OutputDir
+	PackageName string
+
+	// CollectionFormat is the default collectionFormat of array query parameters
+	CollectionFormat string
+
+	// InferParams infers the path, query and body parameters the gin handlers read but do not annotate
+	InferParams bool
+
+	// InferResponses infers the responses the gin handlers write with c.JSON but do not annotate
+	InferResponses bool
+
 	// GeneratedTime whether swag should generate the timestamp at the top of docs.go
 	GeneratedTime bool
 
@@ -127,11 +160,20 @@ type Config struct {
 	// Auto cover old code
 	AutoCoverOld bool
 
-	// GinServerPackage for AutoRegisterGinRouter gen code
+	// GinServerPackage package name of the aggregator router calling every generated module router
 	GinServerPackage string
 
-	// GinRouterPath for AutoRegisterGinRouter gen code
+	// GinRouterPath file or directory of the aggregator router, not generated when empty
 	GinRouterPath string
+
+	// GinRouterLayout where the router of each handler package is generated, see swag.ParseRouterLayout
+	GinRouterLayout string
+	// RouterBackend web framework of the generated routers, see swag.ParseRouterBackend
+	RouterBackend string
+	// RoutesSQLTemplate text/template file rendering the routes.sql output type, swag.DefaultRoutesSQLTemplate when empty
+	RoutesSQLTemplate string
+	// Check regenerates in memory and returns a *swag.StaleFilesError when generated files on disk are out of date
+	Check bool
 }
 
 // Build builds swagger json file  for given searchDir and mainAPIFile. Returns json.
@@ -143,6 +185,12 @@ func (g *Gen) Build(config *Config) error {
 		config.InstanceName = swag.Name
 	}
 
+	if config.LeftTemplateDelim != "" || config.RightTemplateDelim != "" {
+		return ErrTemplateDelims
+	}
+
+	g.writer = &swag.GenWriter{Check: config.Check}
+
 	searchDirs := strings.Split(config.SearchDir, ",")
 	for _, searchDir := range searchDirs {
 		if _, err := os.Stat(searchDir); os.IsNotExist(err) {
@@ -176,6 +224,11 @@ func (g *Gen) Build(config *Config) error {
 		swag.SetExcludedDirsAndFiles(config.Excludes),
 		swag.SetCodeExamplesDirectory(config.CodeExampleFilesDir),
 		swag.SetStrict(config.Strict),
+		swag.SetTags(config.Tags),
+		swag.SetParseExtension(config.ParseExtension),
+		swag.SetCollectionFormat(config.CollectionFormat),
+		swag.SetInferParams(config.InferParams),
+		swag.SetInferResponses(config.InferResponses),
 		swag.SetOverrides(overrides),
 		swag.ParseUsingGoList(config.ParseGoList),
 	)
@@ -190,16 +243,71 @@ func (g *Gen) Build(config *Config) error {
 		return err
 	}
 
+	var (
+		layout      swag.RouterLayout
+		backend     swag.RouterBackend
+		sqlTemplate []byte
+	)
+
+	if config.AutoRegisterGinRouter {
+		var err error
+
+		layout, err = swag.ParseRouterLayout(config.GinRouterLayout)
+		if err != nil {
+			return err
+		}
+
+		backend, err = swag.ParseRouterBackend(config.RouterBackend)
+		if err != nil {
+			return err
+		}
+
+		if config.RoutesSQLTemplate != "" {
+			sqlTemplate, err = os.ReadFile(config.RoutesSQLTemplate)
+			if err != nil {
+				return fmt.Errorf("could not read routes.sql template: %s", err)
+			}
+		}
+
+		// conflicting routes fail the build before any docs are written
+		if err := swag.CheckRouteConflicts(p, backend); err != nil {
+			return err
+		}
+	}
+
 	swagger := p.GetSwagger()
 
-	if err := os.MkdirAll(config.OutputDir, os.ModePerm); err != nil {
+	if err := g.writer.MkdirAll(config.OutputDir); err != nil {
 		return err
 	}
 
+	// Swagger 2.0 has no cookie parameters, oneOf or servers, only the openapi3 output type documents them
+	swagger20Doc, err := swagger20(swagger)
+	if err != nil {
+		return err
+	}
+
+	var routeOutputs []string
+
 	for _, outputType := range config.OutputTypes {
 		outputType = strings.ToLower(strings.TrimSpace(outputType))
+		if swag.IsRouteOutputType(outputType) {
+			if !config.AutoRegisterGinRouter {
+				return fmt.Errorf("output type '%s' is generated with the routers, it needs autoRegisterGinRouter", outputType)
+			}
+
+			routeOutputs = append(routeOutputs, outputType)
+
+			continue
+		}
+
+		doc := swagger20Doc
+		if outputType == openAPI3OutputType {
+			doc = swagger
+		}
+
 		if typeWriter, ok := g.outputTypeMap[outputType]; ok {
-			if err := typeWriter(config, swagger); err != nil {
+			if err := typeWriter(config, doc); err != nil {
 				return err
 			}
 		} else {
@@ -209,13 +317,23 @@ func (g *Gen) Build(config *Config) error {
 
 	if config.AutoRegisterGinRouter {
 		err := swag.GinRouter.RegisterRouter(p, swag.GenConfig{
-			AutoCover: config.AutoCoverOld,
-			OutputDir: config.OutputDir})
+			AutoCover:         config.AutoCoverOld,
+			OutputDir:         config.OutputDir,
+			ServerPackage:     config.GinServerPackage,
+			RouterPath:        config.GinRouterPath,
+			Layout:            layout,
+			Writer:            g.writer,
+			Backend:           backend,
+			RouteOutputs:      routeOutputs,
+			RoutesSQLTemplate: string(sqlTemplate),
+			DocsPackage:       config.PackageName,
+		})
 		if err != nil {
 			return err
 		}
 	}
-	return nil
+
+	return g.writer.Err()
 }
 
 func (g *Gen) writeDocSwagger(config *Config, swagger *spec.Swagger) error {
@@ -233,15 +351,19 @@ func (g *Gen) writeDocSwagger(config *Config, swagger *spec.Swagger) error {
 	}
 
 	packageName := filepath.Base(absOutputDir)
+	if config.PackageName != "" {
+		packageName = config.PackageName
+	}
+
+	docs := &bytes.Buffer{}
 
-	docs, err := os.Create(docFileName)
+	// Write doc
+	err = g.writeGoDoc(packageName, docs, swagger, config)
 	if err != nil {
 		return err
 	}
-	defer docs.Close()
 
-	// Write doc
-	err = g.writeGoDoc(packageName, docs, swagger, config)
+	err = g.writeFile(docs.Bytes(), docFileName)
 	if err != nil {
 		return err
 	}
@@ -304,17 +426,187 @@ func (g *Gen) writeYAMLSwagger(config *Config, swagger *spec.Swagger) error {
 	return nil
 }
 
-func (g *Gen) writeFile(b []byte, file string) error {
-	f, err := os.Create(file)
+// openAPI3OutputType writes openapi.json and openapi.yaml in OpenAPI 3.0.
+const openAPI3OutputType = "openapi3"
+
+func (g *Gen) writeOpenAPI3(config *Config, swagger *spec.Swagger) error {
+	var jsonFileName, yamlFileName = "openapi.json", "openapi.yaml"
+
+	if config.InstanceName != swag.Name {
+		jsonFileName = config.InstanceName + "_" + jsonFileName
+		yamlFileName = config.InstanceName + "_" + yamlFileName
+	}
+
+	doc, err := openapi3.FromSwagger(swagger)
+	if err != nil {
+		return fmt.Errorf("cannot convert to openapi 3.0: %s", err)
+	}
+
+	b, err := g.jsonIndent(doc)
 	if err != nil {
 		return err
 	}
 
-	defer f.Close()
+	err = g.writeFile(b, path.Join(config.OutputDir, jsonFileName))
+	if err != nil {
+		return err
+	}
 
-	_, err = f.Write(b)
+	b, err = g.json(doc)
+	if err != nil {
+		return err
+	}
 
-	return err
+	y, err := g.jsonToYAML(b)
+	if err != nil {
+		return fmt.Errorf("cannot covert json to yaml error: %s", err)
+	}
+
+	err = g.writeFile(y, path.Join(config.OutputDir, yamlFileName))
+	if err != nil {
+		return err
+	}
+
+	g.debug.Printf("create openapi.json and openapi.yaml at  %+v", config.OutputDir)
+
+	return nil
+}
+
+// oneOfExtension lists the alternatives of a oneOf schema in the Swagger 2.0 outputs, which have no oneOf.
+const oneOfExtension = "x-one-of"
+
+// swagger20 returns a copy of swagger without what Swagger 2.0 can not document, which only the openapi3
+// output type keeps: the cookie parameters and the servers of @Server are dropped, and a oneOf schema becomes
+// an untyped schema listing its alternatives in x-one-of.
+func swagger20(swagger *spec.Swagger) (*spec.Swagger, error) {
+	data, err := json.Marshal(swagger)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc spec.Swagger
+
+	err = json.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range doc.Extensions {
+		if strings.EqualFold(name, openapi3.ServersExtension) {
+			delete(doc.Extensions, name)
+		}
+	}
+
+	for name, schema := range doc.Definitions {
+		withoutOneOf(&schema)
+		doc.Definitions[name] = schema
+	}
+
+	for name, param := range doc.Parameters {
+		withoutOneOf(param.Schema)
+		doc.Parameters[name] = param
+	}
+
+	for name, response := range doc.Responses {
+		withoutOneOf(response.Schema)
+		doc.Responses[name] = response
+	}
+
+	if doc.Paths == nil {
+		return &doc, nil
+	}
+
+	for path, item := range doc.Paths.Paths {
+		item.Parameters = withoutCookieParams(item.Parameters)
+
+		for _, operation := range []*spec.Operation{item.Get, item.Put, item.Post, item.Delete, item.Options, item.Head, item.Patch} {
+			if operation == nil {
+				continue
+			}
+
+			operation.Parameters = withoutCookieParams(operation.Parameters)
+
+			if operation.Responses == nil {
+				continue
+			}
+
+			if operation.Responses.Default != nil {
+				withoutOneOf(operation.Responses.Default.Schema)
+			}
+
+			for code, response := range operation.Responses.StatusCodeResponses {
+				withoutOneOf(response.Schema)
+				operation.Responses.StatusCodeResponses[code] = response
+			}
+		}
+
+		doc.Paths.Paths[path] = item
+	}
+
+	return &doc, nil
+}
+
+// withoutCookieParams drops the cookie parameters of params, checking the schema of the others for oneOf.
+func withoutCookieParams(params []spec.Parameter) []spec.Parameter {
+	var result []spec.Parameter
+
+	for _, param := range params {
+		if param.In == "cookie" {
+			continue
+		}
+
+		withoutOneOf(param.Schema)
+		result = append(result, param)
+	}
+
+	return result
+}
+
+// withoutOneOf replaces the oneOf schemas in schema by untyped schemas listing their alternatives in x-one-of.
+func withoutOneOf(schema *spec.Schema) {
+	if schema == nil {
+		return
+	}
+
+	if len(schema.OneOf) > 0 {
+		alternatives := schema.OneOf
+		for i := range alternatives {
+			withoutOneOf(&alternatives[i])
+		}
+
+		*schema = spec.Schema{
+			SchemaProps:      spec.SchemaProps{Title: schema.Title, Description: schema.Description},
+			VendorExtensible: schema.VendorExtensible,
+		}
+		schema.AddExtension(oneOfExtension, alternatives)
+
+		return
+	}
+
+	for name, property := range schema.Properties {
+		withoutOneOf(&property)
+		schema.Properties[name] = property
+	}
+
+	for i := range schema.AllOf {
+		withoutOneOf(&schema.AllOf[i])
+	}
+
+	if schema.Items != nil {
+		withoutOneOf(schema.Items.Schema)
+
+		for i := range schema.Items.Schemas {
+			withoutOneOf(&schema.Items.Schemas[i])
+		}
+	}
+
+	if schema.AdditionalProperties != nil {
+		withoutOneOf(schema.AdditionalProperties.Schema)
+	}
+}
+
+func (g *Gen) writeFile(b []byte, file string) error {
+	return g.writer.WriteFile(file, b)
 }
 
 func (g *Gen) formatSource(src []byte) []byte {
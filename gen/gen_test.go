@@ -34,8 +34,8 @@ func TestGen_Build(t *testing.T) {
 		PropNamingStrategy:    "",
 		AutoRegisterGinRouter: true,
 		AutoCoverOld:          true,
-		GinRouterPath:         "../testdata/simple",
-		GinServerPackage:      "simple",
+		GinRouterPath:         "../testdata/simple/server",
+		GinServerPackage:      "server",
 	}
 	assert.NoError(t, New().Build(config))
 
@@ -54,6 +54,70 @@ func TestGen_Build(t *testing.T) {
 	//}
 }
 
+func TestGen_BuildRouteConflicts(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.20\n",
+		"main.go": "package main\n\n// @title Conflicts\nfunc main() {}\n",
+		"api/api.go": `package api
+
+import "github.com/gin-gonic/gin"
+
+// @Router /users [get]
+func ListUsers(c *gin.Context) {}
+
+// @Router /users [get]
+func SearchUsers(c *gin.Context) {}
+`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0666))
+	}
+
+	config := &Config{
+		SearchDir:             dir,
+		MainAPIFile:           "./main.go",
+		OutputDir:             filepath.Join(dir, "docs"),
+		OutputTypes:           outputTypes,
+		AutoRegisterGinRouter: true,
+	}
+
+	err := New().Build(config)
+	var conflicts swag.RouteConflictsError
+	require.True(t, errors.As(err, &conflicts))
+
+	// nothing is written when the routers can not be registered
+	for _, file := range []string{"docs.go", "swagger.json", "swagger.yaml"} {
+		_, err := os.Stat(filepath.Join(config.OutputDir, file))
+		assert.True(t, os.IsNotExist(err), file)
+	}
+}
+
+func TestGen_Check(t *testing.T) {
+	config := &Config{
+		SearchDir:   searchDir,
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple/docs",
+		OutputTypes: outputTypes,
+	}
+	require.NoError(t, New().Build(config))
+
+	config.Check = true
+	assert.NoError(t, New().Build(config))
+
+	jsonFile := filepath.Join(config.OutputDir, "swagger.json")
+	require.NoError(t, ioutil.WriteFile(jsonFile, []byte("{}"), os.ModePerm))
+
+	err := New().Build(config)
+	var staleErr *swag.StaleFilesError
+	require.True(t, errors.As(err, &staleErr))
+	assert.Equal(t, []string{jsonFile}, staleErr.Files)
+
+	for _, file := range []string{"docs.go", "swagger.json", "swagger.yaml"} {
+		_ = os.Remove(filepath.Join(config.OutputDir, file))
+	}
+}
+
 func TestGen_SpecificOutputTypes(t *testing.T) {
 	config := &Config{
 		SearchDir:          searchDir,
@@ -279,53 +343,9 @@ func TestGen_BuildDocCustomDelims(t *testing.T) {
 		RightTemplateDelim: "%}",
 	}
 
-	require.NoError(t, New().Build(config))
-
-	expectedFiles := []string{
-		filepath.Join(config.OutputDir, "CustomDelims_docs.go"),
-		filepath.Join(config.OutputDir, "CustomDelims_swagger.json"),
-		filepath.Join(config.OutputDir, "CustomDelims_swagger.yaml"),
-	}
-	for _, expectedFile := range expectedFiles {
-		if _, err := os.Stat(expectedFile); os.IsNotExist(err) {
-			require.NoError(t, err)
-		}
-	}
-
-	cmd := exec.Command("go", "build", "-buildmode=plugin", "github.com/swaggo/swag/testdata/delims")
-
-	cmd.Dir = config.SearchDir
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		require.NoError(t, err, string(output))
-	}
-
-	p, err := plugin.Open(filepath.Join(config.SearchDir, "delims.so"))
-	if err != nil {
-		require.NoError(t, err)
-	}
-
-	defer os.Remove("delims.so")
-
-	readDoc, err := p.Lookup("ReadDoc")
-	if err != nil {
-		require.NoError(t, err)
-	}
-
-	jsonOutput := readDoc.(func() string)()
-
-	var jsonDoc interface{}
-	if err := json.Unmarshal([]byte(jsonOutput), &jsonDoc); err != nil {
-		require.NoError(t, err)
-	}
-
-	expectedJSON, err := os.ReadFile(filepath.Join(config.SearchDir, "expected.json"))
-	if err != nil {
-		require.NoError(t, err)
-	}
-
-	assert.JSONEq(t, string(expectedJSON), jsonOutput)
+	// swag.Spec of github.com/swaggo/swag v1.8.4 can not set the delimiters of the generated docs.go
+	assert.ErrorIs(t, New().Build(config), ErrTemplateDelims)
+	assert.NoFileExists(t, filepath.Join(config.OutputDir, "CustomDelims_docs.go"))
 }
 
 func TestGen_jsonIndent(t *testing.T) {
@@ -887,3 +907,39 @@ func TestGen_ErrorAndInterface(t *testing.T) {
 
 	assert.JSONEq(t, string(expectedJSON), string(jsonOutput))
 }
+
+func TestGen_swagger20(t *testing.T) {
+	session := spec.Parameter{ParamProps: spec.ParamProps{Name: "session", In: "cookie"}}
+	id := spec.Parameter{ParamProps: spec.ParamProps{Name: "id", In: "path", Required: true}}
+	oneOf := spec.Schema{SchemaProps: spec.SchemaProps{OneOf: []spec.Schema{*spec.RefSchema("#/definitions/model.User"), *spec.RefSchema("#/definitions/model.Admin")}}}
+
+	swagger := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+		"/users": {PathItemProps: spec.PathItemProps{Get: &spec.Operation{}}},
+		"/users/{id}": {PathItemProps: spec.PathItemProps{
+			Get: &spec.Operation{OperationProps: spec.OperationProps{
+				Parameters: []spec.Parameter{id, session},
+				Responses: &spec.Responses{ResponsesProps: spec.ResponsesProps{StatusCodeResponses: map[int]spec.Response{
+					200: {ResponseProps: spec.ResponseProps{Description: "OK", Schema: spec.ArrayProperty(&oneOf)}},
+				}}},
+			}},
+			Delete: &spec.Operation{OperationProps: spec.OperationProps{Parameters: []spec.Parameter{id}}},
+		}},
+	}}}}
+	swagger.AddExtension("x-servers", []map[string]string{{"url": "https://api.example.com"}})
+
+	result, err := swagger20(swagger)
+	require.NoError(t, err)
+	assert.Equal(t, []spec.Parameter{id}, result.Paths.Paths["/users/{id}"].Get.Parameters)
+	assert.Equal(t, []spec.Parameter{id}, result.Paths.Paths["/users/{id}"].Delete.Parameters)
+	assert.Contains(t, result.Paths.Paths, "/users")
+	assert.NotContains(t, result.Extensions, "x-servers")
+
+	schema, err := json.Marshal(result.Paths.Paths["/users/{id}"].Get.Responses.StatusCodeResponses[200].Schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "array", "items": {"x-one-of": [{"$ref": "#/definitions/model.User"}, {"$ref": "#/definitions/model.Admin"}]}}`, string(schema))
+
+	// the openapi3 output type is generated from the original document
+	assert.Equal(t, []spec.Parameter{id, session}, swagger.Paths.Paths["/users/{id}"].Get.Parameters)
+	assert.Len(t, swagger.Paths.Paths["/users/{id}"].Get.Responses.StatusCodeResponses[200].Schema.Items.Schema.OneOf, 2)
+	assert.Contains(t, swagger.Extensions, "x-servers")
+}
@@ -0,0 +1,71 @@
+package swag
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// StaleFilesError reports generated files whose content differs from a fresh generation.
+type StaleFilesError struct {
+	Files []string
+}
+
+func (e *StaleFilesError) Error() string {
+	return fmt.Sprintf("generated files are out of date, run swag-gin init again: %s", strings.Join(e.Files, ", "))
+}
+
+// GenWriter writes generated files. In check mode it writes nothing and only records
+// the files that are missing or differ from the generated content.
+type GenWriter struct {
+	// Check compares generated content with the files on disk instead of writing them
+	Check bool
+
+	stale []string
+}
+
+// WriteFile writes content to path, or in check mode compares it with the file at path.
+func (w *GenWriter) WriteFile(path string, content []byte) error {
+	if !w.checking() {
+		return os.WriteFile(path, content, 0666)
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err != nil || !bytes.Equal(current, content) {
+		w.stale = append(w.stale, path)
+	}
+
+	return nil
+}
+
+// MkdirAll creates the directory of generated files unless in check mode.
+func (w *GenWriter) MkdirAll(dir string) error {
+	if w.checking() {
+		return nil
+	}
+
+	return os.MkdirAll(dir, os.ModePerm)
+}
+
+// checking reports whether w is in check mode.
+func (w *GenWriter) checking() bool {
+	return w != nil && w.Check
+}
+
+// Err returns a *StaleFilesError listing the stale files found in check mode, if any.
+func (w *GenWriter) Err() error {
+	if w == nil || len(w.stale) == 0 {
+		return nil
+	}
+
+	files := append([]string(nil), w.stale...)
+	sort.Strings(files)
+
+	return &StaleFilesError{Files: files}
+}
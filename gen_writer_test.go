@@ -0,0 +1,61 @@
+package swag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenWriter(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	fresh := filepath.Join(dir, "fresh.go")
+	stale := filepath.Join(dir, "stale.go")
+	missing := filepath.Join(dir, "missing.go")
+
+	var writer *GenWriter
+	require.NoError(t, writer.WriteFile(fresh, []byte("package a\n")))
+	require.NoError(t, (&GenWriter{}).WriteFile(stale, []byte("package a\n")))
+	assert.NoError(t, writer.Err())
+
+	writer = &GenWriter{Check: true}
+	assert.NoError(t, writer.WriteFile(fresh, []byte("package a\n")))
+	assert.NoError(t, writer.Err())
+
+	assert.NoError(t, writer.WriteFile(stale, []byte("package b\n")))
+	assert.NoError(t, writer.WriteFile(missing, []byte("package a\n")))
+	assert.NoError(t, writer.MkdirAll(filepath.Join(dir, "router")))
+
+	err := writer.Err()
+	require.Error(t, err)
+	assert.Equal(t, []string{missing, stale}, err.(*StaleFilesError).Files)
+
+	content, err := os.ReadFile(stale)
+	require.NoError(t, err)
+	assert.Equal(t, "package a\n", string(content))
+	assert.NoFileExists(t, missing)
+	assert.NoDirExists(t, filepath.Join(dir, "router"))
+}
+
+func TestWriteGenFileCheck(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "resource.go")
+	require.NoError(t, os.WriteFile(stale, []byte("package a\n"), 0644))
+
+	assert.NoError(t, writeGenFile(stale, []byte("package b\n"), GenConfig{}))
+	content, err := os.ReadFile(stale)
+	require.NoError(t, err)
+	assert.Equal(t, "package a\n", string(content))
+
+	writer := &GenWriter{Check: true}
+	assert.NoError(t, writeGenFile(stale, []byte("package b\n"), GenConfig{Writer: writer}))
+	err = writer.Err()
+	require.Error(t, err)
+	assert.Equal(t, []string{stale}, err.(*StaleFilesError).Files)
+}
@@ -0,0 +1,59 @@
+package swag
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pathParamPattern matches a path segment that is exactly one Swagger path parameter.
+// A trailing '*' or '+' marks the parameter as catch-all, e.g. {path*} or {proxy+}.
+var pathParamPattern = regexp.MustCompile(`^\{(\w+)([*+]?)}$`)
+
+// catchAllMarkerPattern matches the catch-all marker of an annotated path parameter, which is not Swagger syntax.
+var catchAllMarkerPattern = regexp.MustCompile(`\{(\w+)[*+]}`)
+
+// SpecPath returns the Swagger form of an annotated router path, turning {path*} and {proxy+} into {path} and {proxy}.
+func SpecPath(path string) string {
+	return catchAllMarkerPattern.ReplaceAllString(path, "{$1}")
+}
+
+// GinPath translates an annotated router path into gin route syntax:
+// {id} becomes :id and a catch-all parameter {path*} (or {proxy+}) becomes *path.
+func GinPath(path string) (string, error) {
+	return translatePath(path, "gin", func(name string) string {
+		return ":" + name
+	}, func(name string) string {
+		return "*" + name
+	})
+}
+
+// translatePath rewrites every path parameter segment of an annotated router path with param,
+// or with catchAll for a catch-all parameter, which must be the last segment.
+func translatePath(path, framework string, param, catchAll func(name string) string) (string, error) {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !strings.ContainsAny(segment, "{}") {
+			continue
+		}
+
+		matches := pathParamPattern.FindStringSubmatch(segment)
+		if matches == nil {
+			return "", fmt.Errorf("route %s: %s only supports path parameters spanning a whole segment, got %q", path, framework, segment)
+		}
+
+		if matches[2] == "" {
+			segments[i] = param(matches[1])
+
+			continue
+		}
+
+		if i != len(segments)-1 {
+			return "", fmt.Errorf("route %s: catch-all parameter %q must be the last segment", path, segment)
+		}
+
+		segments[i] = catchAll(matches[1])
+	}
+
+	return strings.Join(segments, "/"), nil
+}
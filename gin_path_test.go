@@ -0,0 +1,43 @@
+package swag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/files/{path}", SpecPath("/files/{path*}"))
+	assert.Equal(t, "/users/{id}", SpecPath("/users/{id}"))
+	assert.Equal(t, "/proxy/{proxy}", SpecPath("/proxy/{proxy+}"))
+}
+
+func TestGinPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/", "/"},
+		{"/users", "/users"},
+		{"/testapi/get-string-by-int/{some_id}", "/testapi/get-string-by-int/:some_id"},
+		{"/users/{id}/posts/{post_id}", "/users/:id/posts/:post_id"},
+		{"/files/{path*}", "/files/*path"},
+		{"/proxy/{proxy+}", "/proxy/*proxy"},
+		{"/users/:id", "/users/:id"},
+	}
+	for _, test := range tests {
+		ginPath, err := GinPath(test.path)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, ginPath)
+	}
+
+	_, err := GinPath("/files/{path*}/meta")
+	assert.Error(t, err)
+
+	_, err = GinPath("/customer/get-wishlist/{wishlist_id}:move")
+	assert.Error(t, err)
+}
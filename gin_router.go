@@ -1,15 +1,18 @@
 package swag
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/dave/jennifer/jen"
 	"github.com/go-openapi/spec"
-	"go/build"
+	"go/ast"
+	goparser "go/parser"
+	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
+	"unicode"
 )
 
 type router struct {
@@ -18,11 +21,42 @@ type router struct {
 type RouteInfos struct {
 	Method     string `json:"method"`      // method
 	Path       string `json:"path"`        //path
+	GinPath    string `json:"gin_path"`    //path in gin route syntax, empty when gin can not express it
 	BasePath   string `json:"base_path"`   //basePath
 	HandlerFun string `json:"handler_fun"` //handlerFun
 	Summary    string `json:"summary"`     //Summary
 	Public     bool   `json:"public"`      //is public router
 	RouteGroup
+	OperationID string            `json:"operation_id"` //@ID
+	Tags        []string          `json:"tags"`         //all tags
+	Security    [][]RouteSecurity `json:"security"`     //alternative security requirements, each requiring all its schemes
+	Deprecated  bool              `json:"deprecated"`   //@Deprecated
+	Description string            `json:"description"`  //Description
+	Consumes    []string          `json:"consumes"`     //accepted mime types
+	Produces    []string          `json:"produces"`     //produced mime types
+	Params      []RouteParam      `json:"params"`       //declared parameters
+	Responses   []int             `json:"responses"`    //documented status codes
+	Middlewares []Middleware      `json:"-"`            //gin middlewares declared by @Middleware
+	handlerPkg  string            //import path of the handler package
+	rawPath     string            //path as annotated by @Router
+	signature   HandlerSignature  //how the router obtains the handler
+	handlerDir  string            //directory of the handler package
+	operation   *spec.Operation   //documented operation
+	bodyType    *ParamGoType      //Go type of the body @Param
+}
+
+// RouteSecurity is a security scheme accepted by a route, with the scopes it requires.
+type RouteSecurity struct {
+	Scheme string   `json:"scheme"`
+	Scopes []string `json:"scopes"`
+}
+
+// RouteParam is a parameter declared by a @Param comment.
+type RouteParam struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
 }
 
 type RouteGroup struct {
@@ -30,14 +64,22 @@ type RouteGroup struct {
 }
 
 type Routes struct {
-	FilePath        string
-	PkgName         string
-	RouteModuleName string
+	FilePath   string //directory of the generated router.go
+	PkgName    string
+	ImportPath string //import path of FilePath when the handlers live there
 }
 
 type GenConfig struct {
-	AutoCover bool   //自动覆盖
-	OutputDir string //输出文件夹
+	AutoCover         bool          //自动覆盖
+	OutputDir         string        //输出文件夹
+	ServerPackage     string        //汇总路由文件的包名
+	RouterPath        string        //汇总路由文件的路径
+	Layout            RouterLayout  //路由文件的布局,默认生成在处理函数的上一级目录
+	Writer            *GenWriter    //写入生成的文件,为nil时直接写入
+	Backend           RouterBackend //生成路由代码使用的web框架,默认gin
+	RouteOutputs      []string      //与resource.go一起导出的路由表: routes.json, routes.yaml, routes.sql
+	RoutesSQLTemplate string        //routes.sql的text/template模板,为空时使用DefaultRoutesSQLTemplate
+	DocsPackage       string        //resource.go的包名,为空时使用输出文件夹名
 }
 
 var GinRouter = new(router)
@@ -45,255 +87,692 @@ var GinRouter = new(router)
 func (*router) RegisterRouter(p *Parser, g GenConfig) error {
 	swagger := p.GetSwagger()
 	routes := make(map[Routes][]RouteInfos)
+	layout := g.Layout
+	if layout == nil {
+		layout = upLayout(1)
+	}
+	routerKeys := make(map[string]Routes)
+	if g.Backend == nil {
+		g.Backend = ginBackend{}
+	}
+	if backend, ok := g.Backend.(basePathBackend); ok {
+		g.Backend = backend.WithBasePath(swagger.BasePath)
+	}
+
+	if err := CheckRouteConflicts(p, g.Backend); err != nil {
+		return err
+	}
 
 	for path, v := range swagger.SwaggerProps.Paths.Paths {
-		method, operation := getRoute(v)
-		groupName := "unknown"
-		if len(operation.Tags) > 0 {
-			groupName = operation.Tags[0]
-		}
-		route := RouteInfos{
-			Method:     method,
-			Path:       path,
-			BasePath:   swagger.BasePath,
-			HandlerFun: p.HandlerFunc[path],
-			Summary:    operation.Summary,
-			RouteGroup: RouteGroup{
-				GroupName: groupName,
-			},
-			Public: len(operation.Security) < 1,
+		for _, r := range getRoutes(v) {
+			operation := r.Operation
+			key := RouteKey{Method: r.Method, Path: path}
+			groupName := "unknown"
+			if len(operation.Tags) > 0 {
+				groupName = operation.Tags[0]
+			}
+			rawPath, ok := p.RoutePath[key]
+			if !ok {
+				rawPath = path
+			}
+			// empty when gin can not express the path another backend serves, CheckRouteConflicts
+			// already rejected the paths the selected backend can not register
+			ginPath, _ := GinPath(rawPath)
+			route := RouteInfos{
+				Method:     strings.ToLower(r.Method),
+				Path:       path,
+				GinPath:    ginPath,
+				BasePath:   swagger.BasePath,
+				HandlerFun: p.HandlerFunc[key],
+				Summary:    operation.Summary,
+				RouteGroup: RouteGroup{
+					GroupName: groupName,
+				},
+				Public:      len(operation.Security) < 1,
+				OperationID: operation.ID,
+				Tags:        operation.Tags,
+				Security:    getRouteSecurity(operation),
+				Deprecated:  operation.Deprecated,
+				Description: operation.Description,
+				Consumes:    operation.Consumes,
+				Produces:    operation.Produces,
+				Params:      getRouteParams(operation),
+				Responses:   getRouteResponses(operation),
+				Middlewares: p.Middlewares[key],
+				rawPath:     rawPath,
+				signature:   p.HandlerSignatures[key],
+				handlerDir:  p.HandlerFuncModules[key],
+				operation:   operation,
+				bodyType:    p.BodyTypes[key],
+			}
+			if route.signature.Err != nil {
+				return fmt.Errorf("%s: %s", route.signature.Pos, route.signature.Err)
+			}
+			if len(route.Consumes) == 0 {
+				route.Consumes = swagger.Consumes
+			}
+			if len(route.Produces) == 0 {
+				route.Produces = swagger.Produces
+			}
+			handlerDir := p.HandlerFuncModules[key]
+			var err error
+			route.handlerPkg, err = GetPackageName(handlerDir)
+			if err != nil {
+				return err
+			}
+			routesKey, ok := routerKeys[handlerDir]
+			if !ok {
+				routesKey, err = getRoutesKey(layout, handlerDir, route.handlerPkg)
+				if err != nil {
+					return err
+				}
+				routerKeys[handlerDir] = routesKey
+			}
+			routes[routesKey] = append(routes[routesKey], route)
 		}
-		routes[Routes{
-			FilePath:        p.FilePathHandlerFunc[path],
-			PkgName:         p.PkgName[path],
-			RouteModuleName: p.HandlerFuncModules[path],
-		}] = append(routes[Routes{
-			FilePath:        p.FilePathHandlerFunc[path],
-			PkgName:         p.PkgName[path],
-			RouteModuleName: p.HandlerFuncModules[path],
-		}], route)
 	}
 	err := genDocFile(routes, g)
 	if err != nil {
 		return err
 	}
-	return genGoFile(routes, g)
+	err = genGoFile(p, routes, g)
+	if err != nil {
+		return err
+	}
+	err = genBindingFiles(routes, g)
+	if err != nil {
+		return err
+	}
+	return genServerFile(p, routes, g)
 }
 
 func genDocFile(routes map[Routes][]RouteInfos, config GenConfig) error {
 	f := jen.NewFilePath(config.OutputDir)
+	if config.DocsPackage != "" {
+		f = jen.NewFilePathName(config.OutputDir, config.DocsPackage)
+	}
+
 	f.Type().Id("RouteGroup").Struct(
 		jen.Id("GroupName").String().Tag(map[string]string{"json": "name"}),
 	)
 	f.Line()
+	f.Type().Id("RouteSecurity").Struct(
+		jen.Id("Scheme").String().Tag(map[string]string{"json": "scheme"}),
+		jen.Id("Scopes").Index().String().Tag(map[string]string{"json": "scopes"}),
+	)
+	f.Line()
+	f.Type().Id("RouteParam").Struct(
+		jen.Id("Name").String().Tag(map[string]string{"json": "name"}),
+		jen.Id("In").String().Tag(map[string]string{"json": "in"}),
+		jen.Id("Type").String().Tag(map[string]string{"json": "type"}),
+		jen.Id("Required").Bool().Tag(map[string]string{"json": "required"}),
+	)
+	f.Line()
 	f.Type().Id("RouteInfos").Struct(
 		jen.Id("Method").String().Tag(map[string]string{"json": "method"}).Comment(" method"),
 		jen.Id("Path").String().Tag(map[string]string{"json": "path"}).Comment(" path"),
+		jen.Id("GinPath").String().Tag(map[string]string{"json": "gin_path"}).Comment(" path in gin route syntax"),
 		jen.Id("BasePath").String().Tag(map[string]string{"json": "base_path"}).Comment(" BasePath"),
 		jen.Id("HandlerFun").String().Tag(map[string]string{"json": "handler_fun"}).Comment(" handlerFun"),
 		jen.Id("Summary").String().Tag(map[string]string{"json": "summary"}).Comment(" Summary"),
 		jen.Id("Public").Bool().Tag(map[string]string{"json": "public"}).Comment(" is public router"),
 		jen.Id("RouteGroup"),
+		jen.Id("OperationID").String().Tag(map[string]string{"json": "operation_id"}).Comment(" @ID"),
+		jen.Id("Tags").Index().String().Tag(map[string]string{"json": "tags"}).Comment(" all tags"),
+		jen.Id("Security").Index().Index().Id("RouteSecurity").Tag(map[string]string{"json": "security"}).Comment(" alternative security requirements, each requiring all its schemes"),
+		jen.Id("Deprecated").Bool().Tag(map[string]string{"json": "deprecated"}).Comment(" @Deprecated"),
+		jen.Id("Description").String().Tag(map[string]string{"json": "description"}).Comment(" Description"),
+		jen.Id("Consumes").Index().String().Tag(map[string]string{"json": "consumes"}).Comment(" accepted mime types"),
+		jen.Id("Produces").Index().String().Tag(map[string]string{"json": "produces"}).Comment(" produced mime types"),
+		jen.Id("Params").Index().Id("RouteParam").Tag(map[string]string{"json": "params"}).Comment(" declared parameters"),
+		jen.Id("Responses").Index().Int().Tag(map[string]string{"json": "responses"}).Comment(" documented status codes"),
 	)
-	var values []jen.Code
+	var all []RouteInfos
 	for _, infos := range routes {
-		for _, info := range infos {
-			values = append(values, jen.Values(jen.Dict{
-				jen.Id("Method"):     jen.Lit(info.Method),
-				jen.Id("Path"):       jen.Lit(info.Path),
-				jen.Id("BasePath"):   jen.Lit(info.BasePath),
-				jen.Id("HandlerFun"): jen.Lit(info.HandlerFun),
-				jen.Id("Summary"):    jen.Lit(info.Summary),
-				jen.Id("Public"):     jen.Lit(info.Public),
-				jen.Id("RouteGroup"): jen.Id("RouteGroup").Values(jen.Dict{
-					jen.Id("GroupName"): jen.Lit(info.GroupName),
-				}),
-			}))
-		}
+		all = append(all, infos...)
+	}
+	sortRouteInfos(all)
+	var values []jen.Code
+	for _, info := range all {
+		values = append(values, routeInfosCode(info))
 	}
 	f.Func().Id("GetRouteInfos").Params().Index().Id("RouteInfos").Block(
 		jen.Return(jen.Index().Id("RouteInfos").Values(values...)))
-	resource := filepath.Join(config.OutputDir, "resource.go")
-	exists, err := pathExists(resource)
+	err := renderFile(f, filepath.Join(config.OutputDir, "resource.go"), config)
 	if err != nil {
 		return err
 	}
-	if !exists || config.AutoCover {
-		file, err := os.Create(resource)
-		if err != nil {
-			return err
+	return genRouteExports(all, config)
+}
+
+// routeInfosCode renders info as a RouteInfos literal of resource.go, leaving out empty fields.
+func routeInfosCode(info RouteInfos) jen.Code {
+	dict := jen.Dict{
+		jen.Id("Method"):     jen.Lit(info.Method),
+		jen.Id("Path"):       jen.Lit(info.Path),
+		jen.Id("GinPath"):    jen.Lit(info.GinPath),
+		jen.Id("BasePath"):   jen.Lit(info.BasePath),
+		jen.Id("HandlerFun"): jen.Lit(info.HandlerFun),
+		jen.Id("Summary"):    jen.Lit(info.Summary),
+		jen.Id("Public"):     jen.Lit(info.Public),
+		jen.Id("RouteGroup"): jen.Id("RouteGroup").Values(jen.Dict{
+			jen.Id("GroupName"): jen.Lit(info.GroupName),
+		}),
+	}
+	if info.OperationID != "" {
+		dict[jen.Id("OperationID")] = jen.Lit(info.OperationID)
+	}
+	if len(info.Tags) > 0 {
+		dict[jen.Id("Tags")] = stringsCode(info.Tags)
+	}
+	if len(info.Security) > 0 {
+		var security []jen.Code
+		for _, requirement := range info.Security {
+			var schemes []jen.Code
+			for _, s := range requirement {
+				value := jen.Dict{jen.Id("Scheme"): jen.Lit(s.Scheme)}
+				if len(s.Scopes) > 0 {
+					value[jen.Id("Scopes")] = stringsCode(s.Scopes)
+				}
+				schemes = append(schemes, jen.Values(value))
+			}
+			security = append(security, jen.Values(schemes...))
 		}
-		err = f.Render(file)
-		if err != nil {
-			return err
+		dict[jen.Id("Security")] = jen.Index().Index().Id("RouteSecurity").Values(security...)
+	}
+	if info.Deprecated {
+		dict[jen.Id("Deprecated")] = jen.Lit(true)
+	}
+	if info.Description != "" {
+		dict[jen.Id("Description")] = jen.Lit(info.Description)
+	}
+	if len(info.Consumes) > 0 {
+		dict[jen.Id("Consumes")] = stringsCode(info.Consumes)
+	}
+	if len(info.Produces) > 0 {
+		dict[jen.Id("Produces")] = stringsCode(info.Produces)
+	}
+	if len(info.Params) > 0 {
+		var params []jen.Code
+		for _, param := range info.Params {
+			params = append(params, jen.Values(jen.Dict{
+				jen.Id("Name"):     jen.Lit(param.Name),
+				jen.Id("In"):       jen.Lit(param.In),
+				jen.Id("Type"):     jen.Lit(param.Type),
+				jen.Id("Required"): jen.Lit(param.Required),
+			}))
 		}
+		dict[jen.Id("Params")] = jen.Index().Id("RouteParam").Values(params...)
 	}
-	return nil
+	if len(info.Responses) > 0 {
+		var codes []jen.Code
+		for _, code := range info.Responses {
+			codes = append(codes, jen.Lit(code))
+		}
+		dict[jen.Id("Responses")] = jen.Index().Int().Values(codes...)
+	}
+	return jen.Values(dict)
 }
 
-func genGoFile(routes map[Routes][]RouteInfos, config GenConfig) error {
+func stringsCode(values []string) jen.Code {
+	var code []jen.Code
+	for _, value := range values {
+		code = append(code, jen.Lit(value))
+	}
+	return jen.Index().String().Values(code...)
+}
+
+func genGoFile(p *Parser, routes map[Routes][]RouteInfos, config GenConfig) error {
 	for filePath, infos := range routes {
-		finalPath := filepath.Join(filePath.FilePath, "router.go")
-		f := jen.NewFilePath(filePath.PkgName)
-		f.ImportName("github.com/gin-gonic/gin", "gin")
-		rParams := jen.Id("r").Op("*").Qual("github.com/gin-gonic/gin", "RouterGroup")
-		var publicCode []jen.Code
-		var privateCode []jen.Code
-		var sortPath []string
-		tempInfos := make(map[string]RouteInfos)
-		for _, v := range infos {
-			sortPath = append(sortPath, v.Path)
-			tempInfos[v.Path] = v
+		err := config.Writer.MkdirAll(filePath.FilePath)
+		if err != nil {
+			return err
+		}
+		err = genHookFile(filePath, config)
+		if err != nil {
+			return err
+		}
+		finalPath := filepath.Join(filePath.FilePath, routerGenFile)
+		f := jen.NewFilePathName(filePath.ImportPath, filePath.PkgName)
+		f.ImportName(config.Backend.Package())
+		controllers := controllerParams(infos)
+		rParams := routerParams(config.Backend, controllers)
+		securityFuncs, err := securityRouterFuncs(p, infos)
+		if err != nil {
+			return err
 		}
-		sort.Strings(sortPath)
-		i := 0
-		for range tempInfos {
-			v := tempInfos[sortPath[i]]
-			i++
-			packageName, err := GetPackageName(filePath.FilePath)
-			split := strings.Split(v.HandlerFun, ".")
-			var handlerFuncName = v.HandlerFun
-			prefix := ""
-			if len(split) > 1 {
-				prefix = split[0]
-				packageName = filepath.Join(packageName, prefix)
-				var temp string
-				handlerFuncName = strings.Replace(v.HandlerFun, prefix+".", temp, 1)
+		funcs := map[string]*routerFunc{publicRouterFunc: newRouterFunc()}
+		for _, funcName := range securityFuncs {
+			funcs[funcName] = newRouterFunc()
+		}
+		sortRouteInfos(infos)
+		for _, v := range infos {
+			handlerFuncName := v.HandlerFun
+			if i := strings.Index(v.HandlerFun, "."); i >= 0 {
+				f.ImportName(v.handlerPkg, v.HandlerFun[:i])
+				handlerFuncName = v.HandlerFun[i+1:]
+			}
+			if v.signature.Kind == HandlerKindTyped {
+				i := strings.LastIndex(handlerFuncName, ".") + 1
+				handlerFuncName = handlerFuncName[:i] + adapterName(handlerFuncName[i:])
+			}
+			handler := jen.Qual(v.handlerPkg, handlerFuncName)
+			if v.signature.Receiver.Type != "" {
+				handler = jen.Id(controllerName(controllers, v.handlerPkg, v.signature.Receiver.Type)).Dot(handlerFuncName[strings.LastIndex(handlerFuncName, ".")+1:])
+			}
+			if v.signature.Factory {
+				var args []jen.Code
+				for _, dep := range v.signature.Args {
+					args = append(args, jen.Id(controllerName(controllers, dep.importPath(v.handlerPkg), dep.Type)))
+				}
+				handler = handler.Call(args...)
 			}
-			packageName = strings.Replace(packageName, "\\", "/", -1)
-			f.ImportName(packageName, prefix)
+			adapted, err := config.Backend.Handler(v.signature.Kind, handler)
+			if err != nil {
+				return fmt.Errorf("%s: %s: %s", v.signature.Pos, v.HandlerFun, err)
+			}
+			routePath, err := config.Backend.Path(v.rawPath)
 			if err != nil {
 				return err
 			}
-			if v.Public {
-				if v.Method == "get" {
-					publicCode = append(publicCode, jen.Id("r").Dot("GET").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
-				if v.Method == "post" {
-					publicCode = append(publicCode, jen.Id("r").Dot("POST").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
-				if v.Method == "head" {
-					publicCode = append(publicCode, jen.Id("r").Dot("HEAD").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
-				if v.Method == "put" {
-					publicCode = append(publicCode, jen.Id("r").Dot("PUT").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
-				if v.Method == "delete" {
-					publicCode = append(publicCode, jen.Id("r").Dot("DELETE").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
-				if v.Method == "options" {
-					publicCode = append(publicCode, jen.Id("r").Dot("OPTIONS").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
-				if v.Method == "patch" {
-					publicCode = append(publicCode, jen.Id("r").Dot("PATCH").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
+			var middlewares []jen.Code
+			for _, middleware := range v.Middlewares {
+				middlewares = append(middlewares, middlewareCode(f, middleware, v.handlerPkg))
+			}
+			fn := funcs[securityRouterFunc(v.Security)]
+			if len(p.TagMiddlewares[v.GroupName]) > 0 {
+				fn.groups[v.GroupName] = append(fn.groups[v.GroupName], config.Backend.Route(v.Method, routePath, true, middlewares, adapted))
 			} else {
-				if v.Method == "get" {
-					privateCode = append(privateCode, jen.Id("r").Dot("GET").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
-				if v.Method == "post" {
-					privateCode = append(privateCode, jen.Id("r").Dot("POST").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
-				if v.Method == "head" {
-					privateCode = append(privateCode, jen.Id("r").Dot("HEAD").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
-				if v.Method == "put" {
-					privateCode = append(privateCode, jen.Id("r").Dot("PUT").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
-				if v.Method == "delete" {
-					privateCode = append(privateCode, jen.Id("r").Dot("DELETE").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
-				if v.Method == "options" {
-					privateCode = append(privateCode, jen.Id("r").Dot("OPTIONS").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
-				if v.Method == "patch" {
-					privateCode = append(privateCode, jen.Id("r").Dot("PATCH").Call(jen.Id("\""+v.Path+"\""), jen.Qual(packageName, handlerFuncName)))
-				}
+				fn.code = append(fn.code, config.Backend.Route(v.Method, routePath, false, middlewares, adapted))
 			}
 		}
-		f.Func().Id("InitPublicRouter").Params(rParams).Block(publicCode...)
-		f.Line()
-		f.Func().Id("InitPrivateRouter").Params(rParams).Block(privateCode...)
-		exists, err := pathExists(finalPath)
+		var privateCode []jen.Code
+		for _, funcName := range append([]string{publicRouterFunc}, securityFuncs...) {
+			fn := funcs[funcName]
+			code := append(fn.code, groupCode(f, config.Backend, fn.groups, p.TagMiddlewares)...)
+			if funcName == publicRouterFunc {
+				code = append(code, jen.Id(publicRoutesHook).Call(jen.Id("r")))
+			} else {
+				privateCode = append(privateCode, jen.Id(funcName).Call(routerArgs(controllers)...))
+			}
+			f.Func().Id(funcName).Params(rParams...).Block(code...)
+			f.Line()
+		}
+		privateCode = append(privateCode, jen.Id(privateRoutesHook).Call(jen.Id("r")))
+		f.Func().Id("InitPrivateRouter").Params(rParams...).Block(privateCode...)
+		err = renderGenFile(f, finalPath, config)
 		if err != nil {
 			return err
 		}
-		if !exists || config.AutoCover {
-			file, err := os.Create(finalPath)
-			if err != nil {
-				return err
-			}
-			err = f.Render(file)
-			if err != nil {
-				return err
+	}
+	return nil
+}
+
+// getRoutesKey locates the router of the handlers in handlerDir according to layout.
+func getRoutesKey(layout RouterLayout, handlerDir, handlerPkg string) (Routes, error) {
+	routerDir, err := layout.RouterDir(handlerDir)
+	if err != nil {
+		return Routes{}, err
+	}
+	pkgName, err := routerPackageName(routerDir)
+	if err != nil {
+		return Routes{}, err
+	}
+	key := Routes{FilePath: routerDir, PkgName: pkgName}
+	if routerDir == filepath.Clean(handlerDir) {
+		key.ImportPath = handlerPkg
+	}
+	return key, nil
+}
+
+// publicRouterFunc registers the routes without any @Security requirement.
+const publicRouterFunc = "InitPublicRouter"
+
+// securitySchemes returns the names of all @securityDefinitions, sorted.
+func securitySchemes(p *Parser) []string {
+	var schemes []string
+	for scheme := range p.GetSwagger().SecurityDefinitions {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// routerFunc collects the registrations of one generated Init*Router function.
+type routerFunc struct {
+	code   []jen.Code
+	groups map[string][]jen.Code
+}
+
+func newRouterFunc() *routerFunc {
+	return &routerFunc{groups: make(map[string][]jen.Code)}
+}
+
+// securityRouterFunc returns the name of the function registering routes secured by security.
+// Alternative requirements share one function, e.g. InitRouterApiKeyOrOAuth2, and the schemes
+// required together are joined with And, e.g. InitRouterApiKeyAndOAuth2.
+func securityRouterFunc(security [][]RouteSecurity) string {
+	if len(security) == 0 {
+		return publicRouterFunc
+	}
+	var alternatives []string
+	for _, requirement := range security {
+		var names []string
+		for _, s := range requirement {
+			names = append(names, exportedIdent(s.Scheme))
+		}
+		alternatives = append(alternatives, strings.Join(names, "And"))
+	}
+	return "InitRouter" + strings.Join(alternatives, "Or")
+}
+
+// securityString writes security like the @Security comments, e.g. ApiKey && OAuth2 || Basic.
+func securityString(security [][]RouteSecurity) string {
+	var alternatives []string
+	for _, requirement := range security {
+		var names []string
+		for _, s := range requirement {
+			names = append(names, s.Scheme)
+		}
+		alternatives = append(alternatives, strings.Join(names, " && "))
+	}
+	return strings.Join(alternatives, " || ")
+}
+
+// securityRouterFuncs returns the names of the functions registering the secured routes of infos, one per
+// @securityDefinitions entry and one per other security of a route, sorted. It fails when two different
+// securities map to the same function name, e.g. the schemes api_key and ApiKey.
+func securityRouterFuncs(p *Parser, infos []RouteInfos) ([]string, error) {
+	owners := make(map[string]string)
+	add := func(security [][]RouteSecurity) error {
+		for _, requirement := range security {
+			for _, s := range requirement {
+				if exportedIdent(s.Scheme) == "" {
+					return fmt.Errorf("security scheme %q has no letter or digit to name its router function", s.Scheme)
+				}
 			}
 		}
+		name, owner := securityRouterFunc(security), securityString(security)
+		if existing, ok := owners[name]; ok && existing != owner {
+			return fmt.Errorf("security %s and %s both generate the router function %s, rename one of the schemes", existing, owner, name)
+		}
+		owners[name] = owner
+		return nil
 	}
-	return nil
+	for _, scheme := range securitySchemes(p) {
+		err := add([][]RouteSecurity{{{Scheme: scheme}}})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, info := range infos {
+		if len(info.Security) == 0 {
+			continue
+		}
+		err := add(info.Security)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var names []string
+	for name := range owners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
 }
 
-func getRoute(pathItem spec.PathItem) (string, *spec.Operation) {
-	if pathItem.Get != nil {
-		return "get", pathItem.Get
+// exportedIdent turns a security scheme name like api_key into an exported Go identifier like ApiKey.
+func exportedIdent(name string) string {
+	var ident strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		ident.WriteString(string(runes))
 	}
-	if pathItem.Post != nil {
-		return "post", pathItem.Post
+	return ident.String()
+}
+
+// groupCode wraps the registrations of each tag in a group of backend carrying the tag middlewares.
+func groupCode(f *jen.File, backend RouterBackend, groups map[string][]jen.Code, tagMiddlewares map[string][]Middleware) []jen.Code {
+	var tags []string
+	for tag := range groups {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	var code []jen.Code
+	for _, tag := range tags {
+		var middlewares []jen.Code
+		for _, middleware := range tagMiddlewares[tag] {
+			middlewares = append(middlewares, middlewareCode(f, middleware, ""))
+		}
+		code = append(code, backend.Group(tag, middlewares, groups[tag]))
+	}
+	return code
+}
+
+// middlewareCode renders a middleware reference, resolving unqualified names against handlerPkg.
+func middlewareCode(f *jen.File, middleware Middleware, handlerPkg string) jen.Code {
+	importPath := middleware.ImportPath
+	if importPath == "" {
+		importPath = handlerPkg
+	}
+	if middleware.Package != "" && middleware.Package != importPackageName(importPath) {
+		f.ImportAlias(importPath, middleware.Package)
+	}
+	code := jen.Qual(importPath, middleware.Name)
+	if middleware.Call {
+		var args []jen.Code
+		for _, arg := range middleware.Args {
+			args = append(args, middlewareArgCode(arg, middleware.ArgImports))
+		}
+		code = code.Call(args...)
+	}
+	return code
+}
+
+// middlewareArgCode renders the source of a middleware argument, qualifying the selectors of the packages in
+// imports so the generated router imports them.
+func middlewareArgCode(arg string, imports map[string]string) jen.Code {
+	expr, err := goparser.ParseExpr(arg)
+	if err != nil || len(imports) == 0 {
+		return jen.Op(arg)
 	}
-	if pathItem.Put != nil {
-		return "put", pathItem.Put
+	var (
+		code []jen.Code
+		last int
+	)
+	ast.Inspect(expr, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := selector.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		importPath, ok := imports[pkg.Name]
+		if !ok {
+			return true
+		}
+		if start := int(selector.Pos()) - 1; start > last {
+			code = append(code, jen.Op(arg[last:start]))
+		}
+		code = append(code, jen.Qual(importPath, selector.Sel.Name))
+		last = int(selector.End()) - 1
+		return false
+	})
+	if last < len(arg) {
+		code = append(code, jen.Op(arg[last:]))
 	}
-	if pathItem.Delete != nil {
-		return "delete", pathItem.Delete
+	return jen.Add(code...)
+}
+
+// getRouteSecurity returns the security requirements of operation, each with its schemes sorted by name
+// and nil scopes for a scheme without any.
+// The requirements of separate @Security comments are alternatives, the schemes of one requirement are all required.
+func getRouteSecurity(operation *spec.Operation) [][]RouteSecurity {
+	var security [][]RouteSecurity
+	seen := make(map[string]bool)
+	for _, requirement := range operation.Security {
+		var schemes []RouteSecurity
+		for scheme, scopes := range requirement {
+			if len(scopes) == 0 {
+				// the parser gives []string{} to schemes declared without scopes
+				scopes = nil
+			}
+			schemes = append(schemes, RouteSecurity{Scheme: scheme, Scopes: scopes})
+		}
+		if len(schemes) == 0 {
+			continue
+		}
+		sort.Slice(schemes, func(i, j int) bool {
+			return schemes[i].Scheme < schemes[j].Scheme
+		})
+		if key := securityString([][]RouteSecurity{schemes}); !seen[key] {
+			seen[key] = true
+			security = append(security, schemes)
+		}
 	}
-	if pathItem.Head != nil {
-		return "head", pathItem.Head
+	sort.SliceStable(security, func(i, j int) bool {
+		return securityString(security[i:i+1]) < securityString(security[j:j+1])
+	})
+	return security
+}
+
+// getRouteParams returns the parameters declared on operation.
+func getRouteParams(operation *spec.Operation) []RouteParam {
+	var params []RouteParam
+	for _, param := range operation.Parameters {
+		params = append(params, RouteParam{
+			Name:     param.Name,
+			In:       param.In,
+			Type:     paramTypeName(param),
+			Required: param.Required,
+		})
 	}
-	if pathItem.Options != nil {
-		return "options", pathItem.Options
+	return params
+}
+
+// paramTypeName describes the type of param, e.g. integer, []string or the definition name of a body.
+func paramTypeName(param spec.Parameter) string {
+	if param.Schema != nil {
+		return schemaTypeName(param.Schema)
 	}
-	if pathItem.Patch != nil {
-		return "patch", pathItem.Patch
+	if param.Type == ARRAY && param.Items != nil {
+		return "[]" + param.Items.Type
 	}
-	return "unknown", nil
+	return param.Type
 }
 
-func pathExists(path string) (bool, error) {
-	_, err := os.Stat(path)
-	if err == nil {
-		return true, nil
+func schemaTypeName(schema *spec.Schema) string {
+	if ref := schema.Ref.String(); ref != "" {
+		return strings.TrimPrefix(ref, "#/definitions/")
 	}
-	if os.IsNotExist(err) {
-		return false, nil
+	if schema.Type.Contains(ARRAY) && schema.Items != nil && schema.Items.Schema != nil {
+		return "[]" + schemaTypeName(schema.Items.Schema)
 	}
-	return false, err
+	if len(schema.Type) > 0 {
+		return schema.Type[0]
+	}
+	return ""
 }
 
-func GetPackageName(searchDir string) (string, error) {
-	s, exists := PkgNameMap[searchDir]
-	if exists {
-		return s, nil
+// getRouteResponses returns the documented status codes of operation, sorted.
+func getRouteResponses(operation *spec.Operation) []int {
+	if operation.Responses == nil {
+		return nil
 	}
-	cmd := exec.Command("go", "list", "-f={{.ImportPath}}")
-	cmd.Dir = searchDir
+	var codes []int
+	for code := range operation.Responses.StatusCodeResponses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return codes
+}
 
-	var stdout, stderr strings.Builder
+// routeOperation is a single operation of a path item together with its HTTP method.
+type routeOperation struct {
+	Method    string
+	Operation *spec.Operation
+}
 
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+// getRoutes returns every operation declared on pathItem, in a fixed method order.
+func getRoutes(pathItem spec.PathItem) []routeOperation {
+	var routes []routeOperation
+	for _, method := range []string{
+		http.MethodGet,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodDelete,
+		http.MethodHead,
+		http.MethodOptions,
+		http.MethodPatch,
+	} {
+		op := refRouteMethodOp(&pathItem, method)
+		if *op != nil {
+			routes = append(routes, routeOperation{Method: method, Operation: *op})
+		}
+	}
+	return routes
+}
 
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("execute go list command, %s, stdout:%s, stderr:%s", err, stdout.String(), stderr.String())
+// renderFile writes f to path through config.Writer unless the file already exists and AutoCover is off,
+// in check mode it is always compared.
+func renderFile(f *jen.File, path string, config GenConfig) error {
+	buf := &bytes.Buffer{}
+	err := f.Render(buf)
+	if err != nil {
+		return err
 	}
+	return writeGenFile(path, buf.Bytes(), config)
+}
 
-	outStr, _ := stdout.String(), stderr.String()
+// renderGenFile writes f to path through config.Writer on every generation, for files nobody edits by hand.
+func renderGenFile(f *jen.File, path string, config GenConfig) error {
+	buf := &bytes.Buffer{}
+	err := f.Render(buf)
+	if err != nil {
+		return err
+	}
+	return config.Writer.WriteFile(path, buf.Bytes())
+}
 
-	if outStr[0] == '_' { // will shown like _/{GOPATH}/src/{YOUR_PACKAGE} when NOT enable GO MODULE.
-		outStr = strings.TrimPrefix(outStr, "_"+build.Default.GOPATH+"/src/")
+// writeGenFile writes content to path, keeping an existing file unless AutoCover.
+// In check mode the file is compared whatever AutoCover is, so a stale file is always reported.
+func writeGenFile(path string, content []byte, config GenConfig) error {
+	exists, err := pathExists(path)
+	if err != nil {
+		return err
+	}
+	if exists && !config.AutoCover && !config.Writer.checking() {
+		return nil
 	}
+	return config.Writer.WriteFile(path, content)
+}
 
-	f := strings.Split(outStr, "\n")
+// sortRouteInfos orders infos by group, path and method so generated files are stable.
+func sortRouteInfos(infos []RouteInfos) {
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].GroupName != infos[j].GroupName {
+			return infos[i].GroupName < infos[j].GroupName
+		}
+		if infos[i].Path != infos[j].Path {
+			return infos[i].Path < infos[j].Path
+		}
+		return infos[i].Method < infos[j].Method
+	})
+}
 
-	outStr = f[0]
-	PkgNameMap[searchDir] = outStr
-	return outStr, nil
+func pathExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
 }
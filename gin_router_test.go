@@ -0,0 +1,167 @@
+package swag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRoutes(t *testing.T) {
+	t.Parallel()
+
+	get, del := &spec.Operation{}, &spec.Operation{}
+	routes := getRoutes(spec.PathItem{PathItemProps: spec.PathItemProps{Get: get, Delete: del}})
+	assert.Equal(t, []routeOperation{
+		{Method: "GET", Operation: get},
+		{Method: "DELETE", Operation: del},
+	}, routes)
+}
+
+func TestRegisterRouterMultipleRoutes(t *testing.T) {
+	t.Parallel()
+
+	src := `package api
+
+import "github.com/gin-gonic/gin"
+
+// @Router /users/{id} [get]
+// @Router /users/{id} [delete]
+// @Router /accounts/{id} [get]
+func User(c *gin.Context) {}
+
+// @Router /users/{id} [put]
+func UpdateUser(c *gin.Context) {}
+`
+	dir := t.TempDir()
+	apiDir := filepath.Join(dir, "router", "api")
+	docsDir := filepath.Join(dir, "docs")
+	require.NoError(t, os.MkdirAll(apiDir, os.ModePerm))
+	require.NoError(t, os.MkdirAll(docsDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.20\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(apiDir, "api.go"), []byte(src), 0644))
+
+	p := New()
+	require.NoError(t, p.packages.ParseFile("example.com/app/router/api", filepath.Join(apiDir, "api.go"), src, ParseAll))
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+	require.NoError(t, GinRouter.RegisterRouter(p, GenConfig{OutputDir: docsDir}))
+
+	router, err := os.ReadFile(filepath.Join(dir, "router", routerGenFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(router), `r.GET("/users/:id", api.User)`)
+	assert.Contains(t, string(router), `r.DELETE("/users/:id", api.User)`)
+	assert.Contains(t, string(router), `r.GET("/accounts/:id", api.User)`)
+	assert.Contains(t, string(router), `r.PUT("/users/:id", api.UpdateUser)`)
+
+	resource, err := os.ReadFile(filepath.Join(docsDir, "resource.go"))
+	require.NoError(t, err)
+	for _, method := range []string{"get", "delete", "put"} {
+		assert.Regexp(t, `Method:\s+"`+method+`"`, string(resource))
+	}
+	assert.Regexp(t, `Path:\s+"/accounts/\{id}"`, string(resource))
+}
+
+func TestMiddlewareCode(t *testing.T) {
+	t.Parallel()
+
+	f := jen.NewFile("router")
+	f.Var().Id("_").Op("=").Add(middlewareCode(f, Middleware{
+		Package:    "authz",
+		ImportPath: "example.com/app/auth",
+		Name:       "Require",
+		Call:       true,
+		Args:       []string{"[]roles.Role{roles.Admin}", `"audit"`},
+		ArgImports: map[string]string{"roles": "example.com/app/roles"},
+	}, ""))
+
+	src := f.GoString()
+	assert.Contains(t, src, `roles "example.com/app/roles"`)
+	assert.Contains(t, src, `authz.Require([]roles.Role{roles.Admin}, "audit")`)
+}
+
+func TestGetRouteSecurity(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil)
+	assert.Empty(t, getRouteSecurity(&operation.Operation))
+
+	assert.NoError(t, operation.ParseComment(`// @Security OAuth2[read, write] && ApiKey`, nil))
+	assert.NoError(t, operation.ParseComment(`// @Security Basic`, nil))
+	assert.NoError(t, operation.ParseComment(`// @Security ApiKey && OAuth2[read, write]`, nil))
+	assert.Equal(t, [][]RouteSecurity{
+		{{Scheme: "ApiKey"}, {Scheme: "OAuth2", Scopes: []string{"read", "write"}}},
+		{{Scheme: "Basic"}},
+	}, getRouteSecurity(&operation.Operation))
+}
+
+func TestSecurityRouterFunc(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "InitPublicRouter", securityRouterFunc(nil))
+	assert.Equal(t, "InitRouterApiKey", securityRouterFunc([][]RouteSecurity{{{Scheme: "api_key"}}}))
+	assert.Equal(t, "InitRouterApiKeyOrOAuth2", securityRouterFunc([][]RouteSecurity{{{Scheme: "ApiKey"}}, {{Scheme: "OAuth2"}}}))
+	assert.Equal(t, "InitRouterApiKeyAndOAuth2OrBasic", securityRouterFunc([][]RouteSecurity{
+		{{Scheme: "ApiKey"}, {Scheme: "OAuth2"}},
+		{{Scheme: "Basic"}},
+	}))
+}
+
+func TestSecurityRouterFuncs(t *testing.T) {
+	t.Parallel()
+
+	p := New()
+	p.swagger.SecurityDefinitions = spec.SecurityDefinitions{
+		"ApiKey": spec.APIKeyAuth("X-API-Key", "header"),
+		"OAuth2": spec.OAuth2Implicit("https://example.com/oauth"),
+	}
+	infos := []RouteInfos{
+		{Security: [][]RouteSecurity{{{Scheme: "ApiKey"}, {Scheme: "OAuth2"}}}},
+		{Security: [][]RouteSecurity{{{Scheme: "ApiKey"}}, {{Scheme: "OAuth2"}}}},
+		{},
+	}
+	names, err := securityRouterFuncs(p, infos)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"InitRouterApiKey", "InitRouterApiKeyAndOAuth2", "InitRouterApiKeyOrOAuth2", "InitRouterOAuth2"}, names)
+
+	p.swagger.SecurityDefinitions["api_key"] = spec.APIKeyAuth("api_key", "query")
+	_, err = securityRouterFuncs(p, infos)
+	assert.EqualError(t, err, "security ApiKey and api_key both generate the router function InitRouterApiKey, rename one of the schemes")
+
+	delete(p.swagger.SecurityDefinitions, "api_key")
+	p.swagger.SecurityDefinitions["__"] = spec.BasicAuth()
+	_, err = securityRouterFuncs(p, infos)
+	assert.Error(t, err)
+}
+
+func TestGetRouteParams(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil)
+	operation.Parameters = []spec.Parameter{
+		*spec.PathParam("id").Typed(INTEGER, ""),
+		*spec.QueryParam("names").CollectionOf(spec.NewItems().Typed(STRING, ""), "csv"),
+		*spec.BodyParam("user", spec.RefSchema("#/definitions/web.User")).AsRequired(),
+		*spec.BodyParam("users", spec.ArrayProperty(spec.RefSchema("#/definitions/web.User"))),
+	}
+	assert.Equal(t, []RouteParam{
+		{Name: "id", In: "path", Type: INTEGER, Required: true},
+		{Name: "names", In: "query", Type: "[]string"},
+		{Name: "user", In: "body", Type: "web.User", Required: true},
+		{Name: "users", In: "body", Type: "[]web.User"},
+	}, getRouteParams(&operation.Operation))
+}
+
+func TestGetRouteResponses(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil)
+	assert.Empty(t, getRouteResponses(&operation.Operation))
+
+	operation.AddResponse(404, spec.NewResponse())
+	operation.AddResponse(200, spec.NewResponse())
+	assert.Equal(t, []int{200, 404}, getRouteResponses(&operation.Operation))
+}
@@ -0,0 +1,121 @@
+package swag
+
+import (
+	"fmt"
+	goparser "go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genServerFile generates the aggregator router at config.RouterPath, calling the
+// router functions of every generated module so main.go only has to call it once.
+func genServerFile(p *Parser, routes map[Routes][]RouteInfos, config GenConfig) error {
+	if config.RouterPath == "" {
+		return nil
+	}
+	if config.ServerPackage == "" {
+		return fmt.Errorf("package name of the aggregator router %s is empty", config.RouterPath)
+	}
+	routerPath := filepath.Clean(config.RouterPath)
+	if filepath.Ext(routerPath) != ".go" {
+		routerPath = filepath.Join(routerPath, "router.go")
+	}
+	err := config.Writer.MkdirAll(filepath.Dir(routerPath))
+	if err != nil {
+		return err
+	}
+	err = checkDirPackage(filepath.Dir(routerPath), config.ServerPackage, routerPath)
+	if err != nil {
+		return err
+	}
+
+	var (
+		modules  []string
+		allInfos []RouteInfos
+	)
+	moduleControllers := make(map[string][]controllerParam)
+	moduleFuncs := make(map[string]map[string]bool)
+	seen := make(map[string]bool)
+	for r, infos := range routes {
+		allInfos = append(allInfos, infos...)
+		if seen[r.FilePath] {
+			continue
+		}
+		seen[r.FilePath] = true
+		if filepath.Clean(r.FilePath) == filepath.Dir(routerPath) {
+			return fmt.Errorf("aggregator router %s would overwrite the router of module %s", routerPath, r.FilePath)
+		}
+		module, err := GetPackageName(r.FilePath)
+		if err != nil {
+			return err
+		}
+		module = strings.Replace(module, "\\", "/", -1)
+		modules = append(modules, module)
+		moduleControllers[module] = controllerParams(infos)
+		funcNames, err := securityRouterFuncs(p, infos)
+		if err != nil {
+			return err
+		}
+		moduleFuncs[module] = make(map[string]bool)
+		for _, funcName := range funcNames {
+			moduleFuncs[module][funcName] = true
+		}
+	}
+	sort.Strings(modules)
+	controllers := controllerParams(allInfos)
+	funcNames, err := securityRouterFuncs(p, allInfos)
+	if err != nil {
+		return err
+	}
+
+	f := jen.NewFile(config.ServerPackage)
+	f.ImportName(config.Backend.Package())
+	rParams := routerParams(config.Backend, controllers)
+	aggregate := func(name, moduleFunc string) {
+		var code []jen.Code
+		for _, module := range modules {
+			if moduleFunc != publicRouterFunc && moduleFunc != "InitPrivateRouter" && !moduleFuncs[module][moduleFunc] {
+				continue
+			}
+			args := []jen.Code{jen.Id("r")}
+			for _, c := range moduleControllers[module] {
+				args = append(args, jen.Id(controllerName(controllers, c.pkg, c.typ)))
+			}
+			code = append(code, jen.Qual(module, moduleFunc).Call(args...))
+		}
+		f.Func().Id(name).Params(rParams...).Block(code...)
+		f.Line()
+	}
+	aggregate("InitAllPublicRouters", publicRouterFunc)
+	aggregate("InitAllPrivateRouters", "InitPrivateRouter")
+	// the modules declare the functions of the securities their routes use, e.g. InitRouterApiKeyOrOAuth2
+	for _, funcName := range funcNames {
+		aggregate("InitAllRouters"+strings.TrimPrefix(funcName, "InitRouter"), funcName)
+	}
+	return renderFile(f, routerPath, config)
+}
+
+// checkDirPackage makes sure the Go files already in dir, other than skip, declare package pkgName.
+func checkDirPackage(dir, pkgName, skip string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file == skip || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		astFile, err := goparser.ParseFile(token.NewFileSet(), file, nil, goparser.PackageClauseOnly)
+		if err != nil {
+			return err
+		}
+		if astFile.Name.Name != pkgName {
+			return fmt.Errorf("%s declares package %s, can not generate router package %s next to it", file, astFile.Name.Name, pkgName)
+		}
+	}
+	return nil
+}
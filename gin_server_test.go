@@ -0,0 +1,63 @@
+package swag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDirPackage(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main_test.go"), []byte("package main_test\n"), 0644))
+
+	assert.NoError(t, checkDirPackage(dir, "main", ""))
+	assert.Error(t, checkDirPackage(dir, "router", ""))
+	assert.NoError(t, checkDirPackage(t.TempDir(), "router", ""))
+
+	routerPath := filepath.Join(dir, "router.go")
+	require.NoError(t, os.WriteFile(routerPath, []byte("package router\n"), 0644))
+	assert.NoError(t, checkDirPackage(dir, "main", routerPath))
+}
+
+func TestGenServerFileSkipped(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, genServerFile(New(), nil, GenConfig{}))
+	assert.Error(t, genServerFile(New(), nil, GenConfig{RouterPath: t.TempDir()}))
+}
+
+func TestGenServerFileSecurity(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.20\n"), 0644))
+
+	p := New()
+	p.swagger.SecurityDefinitions = spec.SecurityDefinitions{
+		"ApiKey": spec.APIKeyAuth("X-API-Key", "header"),
+		"OAuth2": spec.OAuth2Implicit("https://example.com/oauth"),
+	}
+	routes := map[Routes][]RouteInfos{
+		{FilePath: filepath.Join(dir, "user"), PkgName: "user"}: {
+			{Security: [][]RouteSecurity{{{Scheme: "ApiKey"}}, {{Scheme: "OAuth2"}}}},
+		},
+		{FilePath: filepath.Join(dir, "order"), PkgName: "order"}: {
+			{Security: [][]RouteSecurity{{{Scheme: "ApiKey"}, {Scheme: "OAuth2"}}}},
+		},
+	}
+	routerPath := filepath.Join(dir, "router", "router.go")
+	require.NoError(t, genServerFile(p, routes, GenConfig{RouterPath: routerPath, ServerPackage: "router", Backend: ginBackend{}}))
+
+	src, err := os.ReadFile(routerPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "func InitAllRoutersApiKey(r *gin.RouterGroup) {\n\torder.InitRouterApiKey(r)\n\tuser.InitRouterApiKey(r)\n}")
+	assert.Contains(t, string(src), "func InitAllRoutersApiKeyOrOAuth2(r *gin.RouterGroup) {\n\tuser.InitRouterApiKeyOrOAuth2(r)\n}")
+	assert.Contains(t, string(src), "func InitAllRoutersApiKeyAndOAuth2(r *gin.RouterGroup) {\n\torder.InitRouterApiKeyAndOAuth2(r)\n}")
+}
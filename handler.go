@@ -0,0 +1,328 @@
+package swag
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// HandlerKind is the shape of a handler, which the router backend registers directly or adapts.
+type HandlerKind string
+
+const (
+	// HandlerKindGin is a func(*gin.Context) or gin.HandlerFunc.
+	HandlerKindGin HandlerKind = "gin"
+
+	// HandlerKindEcho is a func(echo.Context) error or echo.HandlerFunc.
+	HandlerKindEcho HandlerKind = "echo"
+
+	// HandlerKindHTTPFunc is a func(http.ResponseWriter, *http.Request) or http.HandlerFunc.
+	HandlerKindHTTPFunc HandlerKind = "net/http"
+
+	// HandlerKindHTTP is an http.Handler.
+	HandlerKindHTTP HandlerKind = "http.Handler"
+
+	// HandlerKindTyped is a func(*gin.Context, XInput) (Output, error) called by a generated gin adapter.
+	HandlerKindTyped HandlerKind = "typed"
+)
+
+const (
+	ginImportPath  = "github.com/gin-gonic/gin"
+	echoImportPath = "github.com/labstack/echo/v4"
+	httpImportPath = "net/http"
+)
+
+// HandlerSignature describes how the generated routers obtain the handler of a route.
+type HandlerSignature struct {
+	// Kind is the shape of the handler, or of the handler returned by a factory
+	Kind HandlerKind
+
+	// Receiver is the receiver injected into the routers when the handler is a method without package level instance
+	Receiver HandlerReceiver
+
+	// Owner is the receiver of a method handler
+	Owner HandlerReceiver
+
+	// Input is the name of the input struct generated for a typed handler
+	Input string
+
+	// Factory whether the annotated function returns the handler instead of being it
+	Factory bool
+
+	// Args are the dependencies passed to the factory, in parameter order
+	Args []HandlerDependency
+
+	// Pos is the file:line of the annotated function
+	Pos string
+
+	// Err explains why the handler can not be registered, reported when routers are generated
+	Err error
+
+	// Warn explains an assumption made about a handler that could not be checked, printed when it is parsed
+	Warn error
+}
+
+// HandlerDependency is the type of a value a handler factory is called with, e.g. *sql.DB.
+type HandlerDependency struct {
+	// ImportPath is the import path of the package declaring Type, empty for the package of the handler
+	ImportPath string
+
+	// Type is the name of the type
+	Type string
+
+	// Pointer whether a pointer to Type is injected
+	Pointer bool
+}
+
+// importPath returns the import path of the dependency, handlerPkg when declared next to the handler.
+func (dep HandlerDependency) importPath(handlerPkg string) string {
+	if dep.ImportPath == "" {
+		return handlerPkg
+	}
+
+	return dep.ImportPath
+}
+
+// parseHandlerSignature inspects the signature of an annotated function: a gin, echo or net/http handler
+// is registered itself, a function returning a single handler type is a factory whose parameters are
+// injected into the generated routers. The aliases of handler types are looked up in pkgDefs.
+func parseHandlerSignature(pkgDefs *PackagesDefinitions, astFile *ast.File, decl *ast.FuncDecl) HandlerSignature {
+	var signature HandlerSignature
+
+	signature.Kind = funcHandlerKind(astFile, decl.Type)
+	if signature.Kind == HandlerKindTyped {
+		signature.Input = fieldTypes(decl.Type.Params)[1].(*ast.Ident).Name
+	}
+
+	if signature.Kind != "" {
+		return signature
+	}
+
+	results := decl.Type.Results
+	if results == nil || len(results.List) == 0 {
+		signature.Err = fmt.Errorf("handler %s has signature %s, expected func(*gin.Context), func(echo.Context) error, "+
+			"func(http.ResponseWriter, *http.Request), func(*gin.Context, XInput) (Output, error) or a factory returning one of them",
+			decl.Name.Name, types.ExprString(decl.Type))
+
+		return signature
+	}
+
+	if len(results.List) == 1 && len(results.List[0].Names) <= 1 {
+		var found bool
+
+		signature.Kind, found = resolveHandlerKind(pkgDefs, astFile, results.List[0].Type)
+		if !found {
+			signature.Kind = HandlerKindGin
+			signature.Warn = fmt.Errorf("handler factory %s returns %s, which can not be found, registering its result as a gin.HandlerFunc",
+				decl.Name.Name, types.ExprString(results.List[0].Type))
+		}
+	}
+
+	if signature.Kind == "" {
+		var returned []string
+		for _, result := range fieldTypes(results) {
+			returned = append(returned, types.ExprString(result))
+		}
+
+		signature.Err = fmt.Errorf("handler %s returns (%s), expected a handler or a factory returning a single handler like gin.HandlerFunc",
+			decl.Name.Name, strings.Join(returned, ", "))
+
+		return signature
+	}
+
+	signature.Factory = true
+
+	for _, param := range decl.Type.Params.List {
+		dep, err := parseHandlerDependency(astFile, param.Type)
+		if err != nil {
+			signature.Err = fmt.Errorf("handler factory %s: %s", decl.Name.Name, err)
+
+			return signature
+		}
+
+		for range param.Names {
+			signature.Args = append(signature.Args, dep)
+		}
+
+		if len(param.Names) == 0 {
+			signature.Args = append(signature.Args, dep)
+		}
+	}
+
+	return signature
+}
+
+// typeHandlerKind returns the kind of a handler of type expr, empty when expr is no handler type.
+func typeHandlerKind(astFile *ast.File, expr ast.Expr) HandlerKind {
+	switch {
+	case isQualifiedType(astFile, expr, ginImportPath, "HandlerFunc"):
+		return HandlerKindGin
+	case isQualifiedType(astFile, expr, echoImportPath, "HandlerFunc"):
+		return HandlerKindEcho
+	case isQualifiedType(astFile, expr, httpImportPath, "HandlerFunc"):
+		return HandlerKindHTTPFunc
+	case isQualifiedType(astFile, expr, httpImportPath, "Handler"):
+		return HandlerKindHTTP
+	}
+
+	if funcType, ok := expr.(*ast.FuncType); ok {
+		if kind := funcHandlerKind(astFile, funcType); kind != HandlerKindTyped {
+			return kind
+		}
+	}
+
+	return ""
+}
+
+// maxAliasDepth bounds the chain of aliases followed by resolveHandlerKind.
+const maxAliasDepth = 8
+
+// resolveHandlerKind returns the kind of a handler of type expr like typeHandlerKind, following the aliases
+// declared in the packages of pkgDefs, e.g. type HandlerFunc = gin.HandlerFunc. It reports false for a named
+// type it can not find, whose kind is unknown.
+func resolveHandlerKind(pkgDefs *PackagesDefinitions, astFile *ast.File, expr ast.Expr) (HandlerKind, bool) {
+	for depth := 0; depth < maxAliasDepth; depth++ {
+		if kind := typeHandlerKind(astFile, expr); kind != "" {
+			return kind, true
+		}
+
+		if !isNamedType(expr) {
+			return "", true
+		}
+
+		if pkgDefs == nil {
+			return "", false
+		}
+
+		typeSpecDef := pkgDefs.FindTypeSpec(types.ExprString(expr), astFile)
+		if typeSpecDef == nil {
+			return "", false
+		}
+
+		if !typeSpecDef.TypeSpec.Assign.IsValid() {
+			return "", true
+		}
+
+		astFile, expr = typeSpecDef.File, typeSpecDef.TypeSpec.Type
+	}
+
+	return "", true
+}
+
+// isNamedType reports whether expr names a type declared in a package, like Handler or mw.Handler.
+func isNamedType(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return types.Universe.Lookup(t.Name) == nil
+	case *ast.SelectorExpr:
+		return isIdent(t.X)
+	}
+
+	return false
+}
+
+// funcHandlerKind returns the kind of a handler function of type funcType, empty when it is no handler.
+func funcHandlerKind(astFile *ast.File, funcType *ast.FuncType) HandlerKind {
+	params := fieldTypes(funcType.Params)
+	results := fieldTypes(funcType.Results)
+
+	switch {
+	case len(params) == 1 && len(results) == 0 && isPointerTo(astFile, params[0], ginImportPath, "Context"):
+		return HandlerKindGin
+	case len(params) == 1 && len(results) == 1 && isQualifiedType(astFile, params[0], echoImportPath, "Context") &&
+		types.ExprString(results[0]) == "error":
+		return HandlerKindEcho
+	case len(params) == 2 && len(results) == 0 && isQualifiedType(astFile, params[0], httpImportPath, "ResponseWriter") &&
+		isPointerTo(astFile, params[1], httpImportPath, "Request"):
+		return HandlerKindHTTPFunc
+	case len(params) == 2 && len(results) == 2 && isPointerTo(astFile, params[0], ginImportPath, "Context") &&
+		isIdent(params[1]) && types.ExprString(results[1]) == "error":
+		return HandlerKindTyped
+	}
+
+	return ""
+}
+
+// fieldTypes returns the type of every field in fields, repeated for fields declaring several names.
+func fieldTypes(fields *ast.FieldList) []ast.Expr {
+	if fields == nil {
+		return nil
+	}
+
+	var exprs []ast.Expr
+	for _, field := range fields.List {
+		for i := 0; i < len(field.Names) || i == 0; i++ {
+			exprs = append(exprs, field.Type)
+		}
+	}
+
+	return exprs
+}
+
+// isQualifiedType reports whether expr is the type name of the package importPath as imported by astFile.
+func isQualifiedType(astFile *ast.File, expr ast.Expr, importPath, name string) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != name {
+		return false
+	}
+
+	pkg, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	resolved, ok := findImportPath(astFile, pkg.Name)
+
+	return ok && resolved == importPath
+}
+
+func isIdent(expr ast.Expr) bool {
+	_, ok := expr.(*ast.Ident)
+
+	return ok
+}
+
+// isPointerTo reports whether expr is a pointer to the type name of the package importPath.
+func isPointerTo(astFile *ast.File, expr ast.Expr, importPath, name string) bool {
+	star, ok := expr.(*ast.StarExpr)
+
+	return ok && isQualifiedType(astFile, star.X, importPath, name)
+}
+
+// parseHandlerDependency resolves the type of a factory parameter, only named types can be injected.
+func parseHandlerDependency(astFile *ast.File, expr ast.Expr) (HandlerDependency, error) {
+	var dep HandlerDependency
+
+	typ := expr
+	if star, ok := typ.(*ast.StarExpr); ok {
+		dep.Pointer = true
+		typ = star.X
+	}
+
+	switch t := typ.(type) {
+	case *ast.Ident:
+		if types.Universe.Lookup(t.Name) != nil {
+			return dep, fmt.Errorf("parameter of predeclared type %s can not be injected, wrap it in a named type", types.ExprString(expr))
+		}
+
+		dep.Type = t.Name
+
+		return dep, nil
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		if ok {
+			importPath, found := findImportPath(astFile, pkg.Name)
+			if !found {
+				return dep, fmt.Errorf("can not find import of package %s for parameter type %s", pkg.Name, types.ExprString(expr))
+			}
+
+			dep.ImportPath = importPath
+			dep.Type = t.Sel.Name
+
+			return dep, nil
+		}
+	}
+
+	return dep, fmt.Errorf("parameter of type %s can not be injected, only named types are supported", types.ExprString(expr))
+}
@@ -0,0 +1,135 @@
+package swag
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHandlerSignature(t *testing.T) {
+	t.Parallel()
+
+	src := `package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/labstack/echo/v4"
+	repo "example.com/internal/repository"
+)
+
+func Direct(c *gin.Context) {}
+
+func Echo(c echo.Context) error { return nil }
+
+func Legacy(w http.ResponseWriter, r *http.Request) {}
+
+func Files(root Dir) http.Handler { return nil }
+
+func AnonymousField() {}
+
+func GetUser(c *gin.Context, in GetUserInput) (User, error) { return User{}, nil }
+
+func List(svc Service, db *sql.DB) gin.HandlerFunc { return nil }
+
+func (c *Controller) Show() func(*gin.Context) { return nil }
+
+func Pair(a, b *repo.Store) gin.HandlerFunc { return nil }
+
+func Name(name string) gin.HandlerFunc { return nil }
+
+func Opts(opts ...Option) gin.HandlerFunc { return nil }
+
+func Both() (gin.HandlerFunc, error) { return nil, nil }
+
+func Count() int { return 0 }
+`
+	astFile, err := goparser.ParseFile(token.NewFileSet(), "api.go", src, 0)
+	require.NoError(t, err)
+
+	signatures := make(map[string]HandlerSignature)
+	for _, decl := range astFile.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			signatures[fn.Name.Name] = parseHandlerSignature(nil, astFile, fn)
+		}
+	}
+
+	assert.Equal(t, HandlerSignature{Kind: HandlerKindGin}, signatures["Direct"])
+	assert.Equal(t, HandlerSignature{Kind: HandlerKindEcho}, signatures["Echo"])
+	assert.Equal(t, HandlerSignature{Kind: HandlerKindTyped, Input: "GetUserInput"}, signatures["GetUser"])
+	assert.Equal(t, HandlerSignature{Kind: HandlerKindHTTPFunc}, signatures["Legacy"])
+	assert.Equal(t, HandlerSignature{Kind: HandlerKindHTTP, Factory: true, Args: []HandlerDependency{{Type: "Dir"}}}, signatures["Files"])
+	assert.Equal(t, HandlerSignature{Kind: HandlerKindGin, Factory: true, Args: []HandlerDependency{
+		{Type: "Service"},
+		{ImportPath: "database/sql", Type: "DB", Pointer: true},
+	}}, signatures["List"])
+	assert.Equal(t, HandlerSignature{Kind: HandlerKindGin, Factory: true}, signatures["Show"])
+	assert.Equal(t, HandlerSignature{Kind: HandlerKindGin, Factory: true, Args: []HandlerDependency{
+		{ImportPath: "example.com/internal/repository", Type: "Store", Pointer: true},
+		{ImportPath: "example.com/internal/repository", Type: "Store", Pointer: true},
+	}}, signatures["Pair"])
+
+	assert.EqualError(t, signatures["Name"].Err, "handler factory Name: parameter of predeclared type string can not be injected, wrap it in a named type")
+	assert.EqualError(t, signatures["Opts"].Err, "handler factory Opts: parameter of type ...Option can not be injected, only named types are supported")
+	assert.EqualError(t, signatures["Both"].Err, "handler Both returns (gin.HandlerFunc, error), expected a handler or a factory returning a single handler like gin.HandlerFunc")
+	assert.EqualError(t, signatures["Count"].Err, "handler Count returns (int), expected a handler or a factory returning a single handler like gin.HandlerFunc")
+	assert.EqualError(t, signatures["AnonymousField"].Err, "handler AnonymousField has signature func(), expected func(*gin.Context), func(echo.Context) error, "+
+		"func(http.ResponseWriter, *http.Request), func(*gin.Context, XInput) (Output, error) or a factory returning one of them")
+}
+
+func TestParseHandlerSignatureAlias(t *testing.T) {
+	t.Parallel()
+
+	src := `package api
+
+import "github.com/gin-gonic/gin"
+
+type HandlerFunc = gin.HandlerFunc
+
+type Handler = HandlerFunc
+
+type Defined gin.HandlerFunc
+
+func Aliased() HandlerFunc { return nil }
+
+func Chained() Handler { return nil }
+
+func Named() Defined { return nil }
+
+func Unknown() mw.Handler { return nil }
+`
+	p := New()
+	require.NoError(t, p.packages.ParseFile("api", "api/api.go", src, ParseAll))
+	_, err := p.packages.ParseTypes()
+	require.NoError(t, err)
+
+	signatures := make(map[string]HandlerSignature)
+	for astFile := range p.packages.files {
+		for _, decl := range astFile.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok {
+				signatures[fn.Name.Name] = parseHandlerSignature(p.packages, astFile, fn)
+			}
+		}
+	}
+
+	assert.Equal(t, HandlerSignature{Kind: HandlerKindGin, Factory: true}, signatures["Aliased"])
+	assert.Equal(t, HandlerSignature{Kind: HandlerKindGin, Factory: true}, signatures["Chained"])
+	assert.EqualError(t, signatures["Named"].Err, "handler Named returns (Defined), expected a handler or a factory returning a single handler like gin.HandlerFunc")
+
+	assert.Equal(t, HandlerKindGin, signatures["Unknown"].Kind)
+	assert.NoError(t, signatures["Unknown"].Err)
+	assert.EqualError(t, signatures["Unknown"].Warn, "handler factory Unknown returns mw.Handler, which can not be found, registering its result as a gin.HandlerFunc")
+}
+
+func TestHandlerDependencyImportPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "example.com/api", HandlerDependency{Type: "Service"}.importPath("example.com/api"))
+	assert.Equal(t, "database/sql", HandlerDependency{ImportPath: "database/sql", Type: "DB"}.importPath("example.com/api"))
+}
@@ -0,0 +1,394 @@
+package swag
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// ginParamReaders are the *gin.Context methods reading a parameter by name, with the @Param location and type.
+var ginParamReaders = map[string][2]string{
+	"Param":         {"path", STRING},
+	"Query":         {"query", STRING},
+	"DefaultQuery":  {"query", STRING},
+	"GetQuery":      {"query", STRING},
+	"QueryArray":    {"query", "[]" + STRING},
+	"GetQueryArray": {"query", "[]" + STRING},
+}
+
+// ginBinders are the *gin.Context methods binding a struct, with the @Param location of the struct.
+var ginBinders = map[string]string{
+	"ShouldBindJSON":  "body",
+	"BindJSON":        "body",
+	"ShouldBindXML":   "body",
+	"BindXML":         "body",
+	"ShouldBindYAML":  "body",
+	"BindYAML":        "body",
+	"ShouldBindQuery": "query",
+	"BindQuery":       "query",
+	"ShouldBindUri":   "uri",
+	"BindUri":         "uri",
+}
+
+// SetInferParams infers the path, query and body parameters the gin handlers read but do not annotate.
+func SetInferParams(inferParams bool) func(*Parser) {
+	return func(p *Parser) {
+		p.inferParams = inferParams
+	}
+}
+
+// usedParams are the parameters a gin handler reads from its *gin.Context.
+type usedParams struct {
+	// comments are @Param comments without the attribute, like `id path string true "id"`
+	comments []string
+
+	// uriTypes are the structs bound with ShouldBindUri, whose uri tagged fields are path parameters
+	uriTypes []string
+}
+
+// inferOperationParams adds the parameters the handler decl reads from its *gin.Context to operation, the
+// annotated parameters always win, and reports a parameter used in another location than annotated, a body
+// bound to another type than annotated or a path parameter missing in the route.
+func (parser *Parser) inferOperationParams(operation *Operation, astFile *ast.File, decl *ast.FuncDecl, handler string) error {
+	if decl.Body == nil || len(operation.RouterProperties) == 0 {
+		return nil
+	}
+
+	used := handlerParams(astFile, decl)
+
+	for _, uriType := range used.uriTypes {
+		typeSpecDef := parser.packages.FindTypeSpec(uriType, astFile)
+		if typeSpecDef == nil {
+			parser.debug.Printf("warning: %s: can not find the uri struct %s to infer its path params", handler, uriType)
+
+			continue
+		}
+
+		used.comments = append(used.comments, uriParamComments(typeSpecDef.TypeSpec)...)
+	}
+
+	inferred := NewOperation(parser)
+	for _, comment := range used.comments {
+		err := inferred.ParseParamComment(comment, astFile)
+		if err != nil {
+			parser.debug.Printf("warning: %s: can not infer param %s: %s", handler, comment, err)
+		}
+	}
+
+	for _, param := range inferred.Parameters {
+		merge, err := paramConflict(operation, param)
+		if err == nil && param.In == "path" {
+			err = missingPathParam(operation.RouterProperties, param.Name)
+			merge = err == nil
+		}
+
+		if err != nil {
+			err = fmt.Errorf("%s: %s", handler, err)
+			if parser.Strict {
+				return err
+			}
+
+			parser.debug.Printf("warning: %s\n", err)
+		}
+
+		if !merge {
+			continue
+		}
+
+		operation.Parameters = append(operation.Parameters, param)
+		if param.In == "body" {
+			operation.BodyType = inferred.BodyType
+		}
+	}
+
+	return nil
+}
+
+// paramConflict reports whether the inferred param is missing in the annotations of operation, and the conflict
+// of param with the annotated parameter of the same name, or with the annotated body.
+func paramConflict(operation *Operation, param spec.Parameter) (bool, error) {
+	for _, declared := range operation.Parameters {
+		if param.In == "body" && declared.In == "body" {
+			inferredSchema, _ := json.Marshal(param.Schema)
+			declaredSchema, _ := json.Marshal(declared.Schema)
+
+			if string(inferredSchema) != string(declaredSchema) {
+				return false, fmt.Errorf("body %s is bound to another type than the annotated body %s", param.Name, declared.Name)
+			}
+
+			return false, nil
+		}
+
+		if declared.Name != param.Name || declared.In == "body" || param.In == "body" {
+			continue
+		}
+
+		if declared.In != param.In {
+			return false, fmt.Errorf("%s param %s is annotated as a %s param", param.In, param.Name, declared.In)
+		}
+
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// missingPathParam returns an error when a route of routes has no path parameter name.
+func missingPathParam(routes []RouteProperties, name string) error {
+	for _, route := range routes {
+		found := false
+		for _, segment := range strings.Split(route.RawPath, "/") {
+			matches := pathParamPattern.FindStringSubmatch(segment)
+			if matches != nil && matches[1] == name {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("path param %s is not in the route %s %s", name, strings.ToUpper(route.HTTPMethod), route.RawPath)
+		}
+	}
+
+	return nil
+}
+
+// handlerParams walks the body of decl, and of the gin handlers it returns, for the parameters read from a
+// *gin.Context. Only string literal names are inferred, a bound variable must be declared in the body.
+func handlerParams(astFile *ast.File, decl *ast.FuncDecl) usedParams {
+	var used usedParams
+
+	contexts := ginContexts(astFile, decl)
+	if len(contexts) == 0 {
+		return used
+	}
+
+	vars := localVarTypes(decl.Body)
+	seen := make(map[string]bool)
+
+	add := func(comment string) {
+		if !seen[comment] {
+			seen[comment] = true
+			used.comments = append(used.comments, comment)
+		}
+	}
+
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		receiver, ok := selector.X.(*ast.Ident)
+		if !ok || !contexts[receiver.Name] {
+			return true
+		}
+
+		if reader, ok := ginParamReaders[selector.Sel.Name]; ok {
+			name, ok := stringLiteral(call.Args[0])
+			if !ok || name == "" {
+				return true
+			}
+
+			comment := fmt.Sprintf("%s %s %s %t \"%s\"", name, reader[0], reader[1], reader[0] == "path", name)
+			if selector.Sel.Name == "DefaultQuery" && len(call.Args) == 2 {
+				value, ok := stringLiteral(call.Args[1])
+				if ok && value != "" && !strings.ContainsAny(value, `()"`) {
+					comment += " default(" + value + ")"
+				}
+			}
+
+			add(comment)
+
+			return true
+		}
+
+		if in, ok := ginBinders[selector.Sel.Name]; ok {
+			name, typ := boundVar(call.Args[0], vars)
+			if typ == nil {
+				return true
+			}
+
+			switch in {
+			case "uri":
+				used.uriTypes = append(used.uriTypes, types.ExprString(typ))
+			default:
+				add(fmt.Sprintf("%s %s %s %t \"%s\"", name, in, types.ExprString(typ), in == "body", name))
+			}
+		}
+
+		return true
+	})
+
+	return used
+}
+
+// ginContexts returns the names of the *gin.Context parameters of decl and of the function literals in its body.
+func ginContexts(astFile *ast.File, decl *ast.FuncDecl) map[string]bool {
+	contexts := make(map[string]bool)
+
+	addParams := func(funcType *ast.FuncType) {
+		if funcType.Params == nil {
+			return
+		}
+
+		for _, param := range funcType.Params.List {
+			if !isPointerTo(astFile, param.Type, ginImportPath, "Context") {
+				continue
+			}
+
+			for _, name := range param.Names {
+				contexts[name.Name] = true
+			}
+		}
+	}
+
+	addParams(decl.Type)
+
+	if decl.Body != nil {
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			if funcLit, ok := node.(*ast.FuncLit); ok {
+				addParams(funcLit.Type)
+			}
+
+			return true
+		})
+	}
+
+	return contexts
+}
+
+// localVarTypes returns the types of the variables declared in body by var name T, var name = T{},
+// name := T{}, name := &T{} or name := new(T), pointers dereferenced.
+func localVarTypes(body *ast.BlockStmt) map[string]ast.Expr {
+	vars := make(map[string]ast.Expr)
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch stmt := node.(type) {
+		case *ast.ValueSpec:
+			for i, name := range stmt.Names {
+				switch {
+				case stmt.Type != nil:
+					vars[name.Name] = derefType(stmt.Type)
+				case i < len(stmt.Values):
+					if typ := valueType(stmt.Values[i]); typ != nil {
+						vars[name.Name] = typ
+					}
+				}
+			}
+		case *ast.AssignStmt:
+			if stmt.Tok != token.DEFINE || len(stmt.Lhs) != len(stmt.Rhs) {
+				return true
+			}
+
+			for i, lhs := range stmt.Lhs {
+				name, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+
+				if typ := valueType(stmt.Rhs[i]); typ != nil {
+					vars[name.Name] = typ
+				}
+			}
+		}
+
+		return true
+	})
+
+	return vars
+}
+
+// valueType returns the type of a T{}, &T{} or new(T) value, nil for other values.
+func valueType(value ast.Expr) ast.Expr {
+	switch v := value.(type) {
+	case *ast.CompositeLit:
+		return v.Type
+	case *ast.UnaryExpr:
+		if lit, ok := v.X.(*ast.CompositeLit); ok && v.Op == token.AND {
+			return lit.Type
+		}
+	case *ast.CallExpr:
+		if fun, ok := v.Fun.(*ast.Ident); ok && fun.Name == "new" && len(v.Args) == 1 {
+			return v.Args[0]
+		}
+	}
+
+	return nil
+}
+
+func derefType(typ ast.Expr) ast.Expr {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		return star.X
+	}
+
+	return typ
+}
+
+// boundVar returns the name and type of the variable arg, like &req, passed to a binding method.
+func boundVar(arg ast.Expr, vars map[string]ast.Expr) (string, ast.Expr) {
+	if unary, ok := arg.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		arg = unary.X
+	}
+
+	ident, ok := arg.(*ast.Ident)
+	if !ok {
+		return "", nil
+	}
+
+	return ident.Name, vars[ident.Name]
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+
+	value, err := strconv.Unquote(lit.Value)
+
+	return value, err == nil
+}
+
+// uriParamComments returns the @Param comments of the uri tagged fields of primitive types of the struct typeSpec.
+func uriParamComments(typeSpec *ast.TypeSpec) []string {
+	structType, ok := typeSpec.Type.(*ast.StructType)
+	if !ok {
+		return nil
+	}
+
+	var comments []string
+
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+
+		name := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("uri")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		typ, ok := derefType(field.Type).(*ast.Ident)
+		if !ok || !IsGolangPrimitiveType(typ.Name) {
+			continue
+		}
+
+		comments = append(comments, fmt.Sprintf("%s path %s true \"%s\"", name, typ.Name, name))
+	}
+
+	return comments
+}
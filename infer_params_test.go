@@ -0,0 +1,156 @@
+package swag
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+const inferParamsSrc = `package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/example/app/model"
+)
+
+type GetUserUri struct {
+	ID      int    ` + "`uri:\"id\" binding:\"required\"`" + `
+	Section string ` + "`uri:\"section\"`" + `
+	Skipped model.ID
+}
+
+func UpdateUser(c *gin.Context) {
+	var req model.UpdateUserReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return
+	}
+
+	filter := &model.Filter{}
+	_ = c.ShouldBindQuery(filter)
+
+	uri := GetUserUri{}
+	_ = c.ShouldBindUri(&uri)
+
+	_ = c.Param("id")
+	_ = c.Param("id")
+	_ = c.DefaultQuery("limit", "10")
+	_ = c.QueryArray("tags")
+	_ = c.Query(limitKey)
+	_ = c.GetHeader("X-Request-ID")
+}
+
+func NewListUsers(db *DB) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		page := new(Page)
+		_ = ctx.BindQuery(page)
+		_ = ctx.GetQuery("q")
+	}
+}
+
+func Health(w http.ResponseWriter, r *http.Request) {
+	_ = r.URL.Query().Get("verbose")
+}
+`
+
+func parseInferParamsSrc(t *testing.T) *ast.File {
+	t.Helper()
+
+	astFile, err := goparser.ParseFile(token.NewFileSet(), "api.go", inferParamsSrc, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	return astFile
+}
+
+func findFuncDecl(astFile *ast.File, name string) *ast.FuncDecl {
+	for _, decl := range astFile.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Name.Name == name {
+			return funcDecl
+		}
+	}
+
+	return nil
+}
+
+func TestHandlerParams(t *testing.T) {
+	t.Parallel()
+
+	astFile := parseInferParamsSrc(t)
+
+	used := handlerParams(astFile, findFuncDecl(astFile, "UpdateUser"))
+	assert.Equal(t, []string{
+		`req body model.UpdateUserReq true "req"`,
+		`filter query model.Filter false "filter"`,
+		`id path string true "id"`,
+		`limit query string false "limit" default(10)`,
+		`tags query []string false "tags"`,
+	}, used.comments)
+	assert.Equal(t, []string{"GetUserUri"}, used.uriTypes)
+
+	used = handlerParams(astFile, findFuncDecl(astFile, "NewListUsers"))
+	assert.Equal(t, []string{`page query Page false "page"`, `q query string false "q"`}, used.comments)
+
+	assert.Empty(t, handlerParams(astFile, findFuncDecl(astFile, "Health")))
+}
+
+func TestUriParamComments(t *testing.T) {
+	t.Parallel()
+
+	astFile := parseInferParamsSrc(t)
+	typeSpec := astFile.Decls[1].(*ast.GenDecl).Specs[0].(*ast.TypeSpec)
+
+	assert.Equal(t, []string{`id path int true "id"`, `section path string true "section"`}, uriParamComments(typeSpec))
+}
+
+func TestParamConflict(t *testing.T) {
+	t.Parallel()
+
+	operation := &Operation{}
+	operation.Parameters = []spec.Parameter{
+		*spec.PathParam("id"),
+		*spec.QueryParam("limit"),
+		*spec.BodyParam("user", spec.RefSchema("#/definitions/model.User")),
+	}
+
+	merge, err := paramConflict(operation, *spec.PathParam("id"))
+	assert.False(t, merge)
+	assert.NoError(t, err)
+
+	merge, err = paramConflict(operation, *spec.QueryParam("id"))
+	assert.False(t, merge)
+	assert.EqualError(t, err, "query param id is annotated as a path param")
+
+	merge, err = paramConflict(operation, *spec.QueryParam("offset"))
+	assert.True(t, merge)
+	assert.NoError(t, err)
+
+	merge, err = paramConflict(operation, *spec.BodyParam("req", spec.RefSchema("#/definitions/model.User")))
+	assert.False(t, merge)
+	assert.NoError(t, err)
+
+	merge, err = paramConflict(operation, *spec.BodyParam("req", spec.RefSchema("#/definitions/model.UpdateUserReq")))
+	assert.False(t, merge)
+	assert.EqualError(t, err, "body req is bound to another type than the annotated body user")
+
+	merge, err = paramConflict(&Operation{}, *spec.BodyParam("req", spec.RefSchema("#/definitions/model.User")))
+	assert.True(t, merge)
+	assert.NoError(t, err)
+}
+
+func TestMissingPathParam(t *testing.T) {
+	t.Parallel()
+
+	routes := []RouteProperties{
+		{HTTPMethod: "get", Path: "/users/{id}/files/{path}", RawPath: "/users/{id}/files/{path*}"},
+		{HTTPMethod: "head", Path: "/users/{id}", RawPath: "/users/{id}"},
+	}
+
+	assert.NoError(t, missingPathParam(routes, "id"))
+	assert.EqualError(t, missingPathParam(routes, "path"), "path param path is not in the route HEAD /users/{id}")
+	assert.EqualError(t, missingPathParam(routes[:1], "name"), "path param name is not in the route GET /users/{id}/files/{path*}")
+}
@@ -0,0 +1,350 @@
+package swag
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/go-openapi/spec"
+)
+
+// ginJSONWriters are the *gin.Context methods writing a status code and a JSON body.
+var ginJSONWriters = map[string]bool{
+	"JSON":                true,
+	"IndentedJSON":        true,
+	"SecureJSON":          true,
+	"PureJSON":            true,
+	"AsciiJSON":           true,
+	"JSONP":               true,
+	"AbortWithStatusJSON": true,
+}
+
+// httpStatusCodes maps the names of the net/http status constants, like StatusOK, to their codes.
+var httpStatusCodes = map[string]int{
+	"StatusContinue":                      http.StatusContinue,
+	"StatusSwitchingProtocols":            http.StatusSwitchingProtocols,
+	"StatusProcessing":                    http.StatusProcessing,
+	"StatusEarlyHints":                    http.StatusEarlyHints,
+	"StatusOK":                            http.StatusOK,
+	"StatusCreated":                       http.StatusCreated,
+	"StatusAccepted":                      http.StatusAccepted,
+	"StatusNonAuthoritativeInfo":          http.StatusNonAuthoritativeInfo,
+	"StatusNoContent":                     http.StatusNoContent,
+	"StatusResetContent":                  http.StatusResetContent,
+	"StatusPartialContent":                http.StatusPartialContent,
+	"StatusMultiStatus":                   http.StatusMultiStatus,
+	"StatusAlreadyReported":               http.StatusAlreadyReported,
+	"StatusIMUsed":                        http.StatusIMUsed,
+	"StatusMultipleChoices":               http.StatusMultipleChoices,
+	"StatusMovedPermanently":              http.StatusMovedPermanently,
+	"StatusFound":                         http.StatusFound,
+	"StatusSeeOther":                      http.StatusSeeOther,
+	"StatusNotModified":                   http.StatusNotModified,
+	"StatusUseProxy":                      http.StatusUseProxy,
+	"StatusTemporaryRedirect":             http.StatusTemporaryRedirect,
+	"StatusPermanentRedirect":             http.StatusPermanentRedirect,
+	"StatusBadRequest":                    http.StatusBadRequest,
+	"StatusUnauthorized":                  http.StatusUnauthorized,
+	"StatusPaymentRequired":               http.StatusPaymentRequired,
+	"StatusForbidden":                     http.StatusForbidden,
+	"StatusNotFound":                      http.StatusNotFound,
+	"StatusMethodNotAllowed":              http.StatusMethodNotAllowed,
+	"StatusNotAcceptable":                 http.StatusNotAcceptable,
+	"StatusProxyAuthRequired":             http.StatusProxyAuthRequired,
+	"StatusRequestTimeout":                http.StatusRequestTimeout,
+	"StatusConflict":                      http.StatusConflict,
+	"StatusGone":                          http.StatusGone,
+	"StatusLengthRequired":                http.StatusLengthRequired,
+	"StatusPreconditionFailed":            http.StatusPreconditionFailed,
+	"StatusRequestEntityTooLarge":         http.StatusRequestEntityTooLarge,
+	"StatusRequestURITooLong":             http.StatusRequestURITooLong,
+	"StatusUnsupportedMediaType":          http.StatusUnsupportedMediaType,
+	"StatusRequestedRangeNotSatisfiable":  http.StatusRequestedRangeNotSatisfiable,
+	"StatusExpectationFailed":             http.StatusExpectationFailed,
+	"StatusTeapot":                        http.StatusTeapot,
+	"StatusMisdirectedRequest":            http.StatusMisdirectedRequest,
+	"StatusUnprocessableEntity":           http.StatusUnprocessableEntity,
+	"StatusLocked":                        http.StatusLocked,
+	"StatusFailedDependency":              http.StatusFailedDependency,
+	"StatusTooEarly":                      http.StatusTooEarly,
+	"StatusUpgradeRequired":               http.StatusUpgradeRequired,
+	"StatusPreconditionRequired":          http.StatusPreconditionRequired,
+	"StatusTooManyRequests":               http.StatusTooManyRequests,
+	"StatusRequestHeaderFieldsTooLarge":   http.StatusRequestHeaderFieldsTooLarge,
+	"StatusUnavailableForLegalReasons":    http.StatusUnavailableForLegalReasons,
+	"StatusInternalServerError":           http.StatusInternalServerError,
+	"StatusNotImplemented":                http.StatusNotImplemented,
+	"StatusBadGateway":                    http.StatusBadGateway,
+	"StatusServiceUnavailable":            http.StatusServiceUnavailable,
+	"StatusGatewayTimeout":                http.StatusGatewayTimeout,
+	"StatusHTTPVersionNotSupported":       http.StatusHTTPVersionNotSupported,
+	"StatusVariantAlsoNegotiates":         http.StatusVariantAlsoNegotiates,
+	"StatusInsufficientStorage":           http.StatusInsufficientStorage,
+	"StatusLoopDetected":                  http.StatusLoopDetected,
+	"StatusNotExtended":                   http.StatusNotExtended,
+	"StatusNetworkAuthenticationRequired": http.StatusNetworkAuthenticationRequired,
+}
+
+// SetInferResponses infers the responses the gin handlers write with c.JSON but do not annotate.
+func SetInferResponses(inferResponses bool) func(*Parser) {
+	return func(p *Parser) {
+		p.inferResponses = inferResponses
+	}
+}
+
+// usedResponse is a response a gin handler writes with c.JSON.
+type usedResponse struct {
+	code int
+
+	// typ is the Go type of the body, in the syntax of a @Success comment, empty when it can not be resolved
+	typ string
+
+	// expr is the source of a body whose type can not be resolved, like resp in c.JSON(200, resp)
+	expr string
+}
+
+// inferOperationResponses adds the responses the handler decl writes with c.JSON to operation when their status is
+// not annotated, and reports the responses written with another type than the annotated schema of their status.
+func (parser *Parser) inferOperationResponses(operation *Operation, astFile *ast.File, decl *ast.FuncDecl, handler string) error {
+	if decl.Body == nil || len(operation.RouterProperties) == 0 {
+		return nil
+	}
+
+	annotated := make(map[int]spec.Response)
+	for code, response := range operation.Responses.StatusCodeResponses {
+		annotated[code] = response
+	}
+
+	// inferredTypes holds the type documented for each inferred status
+	inferredTypes := make(map[int]string)
+
+	for _, used := range handlerResponses(astFile, decl) {
+		if used.typ == "" {
+			if _, ok := annotated[used.code]; !ok {
+				parser.debug.Printf("warning: %s: can not infer the type of %s written with status %d, annotate it", handler, used.expr, used.code)
+			}
+
+			continue
+		}
+
+		if typ, ok := inferredTypes[used.code]; ok {
+			if typ != used.typ {
+				parser.debug.Printf("warning: %s: responds %d with %s and %s, only %s is documented", handler, used.code, typ, used.typ, typ)
+			}
+
+			continue
+		}
+
+		inferred := NewOperation(parser)
+
+		err := inferred.ParseResponseComment(fmt.Sprintf("%d {object} %s", used.code, used.typ), astFile)
+		if err != nil {
+			parser.debug.Printf("warning: %s: can not infer response %d %s: %s", handler, used.code, used.typ, err)
+
+			continue
+		}
+
+		response := inferred.Responses.StatusCodeResponses[used.code]
+
+		declared, ok := annotated[used.code]
+		if !ok {
+			inferredTypes[used.code] = used.typ
+			operation.AddResponse(used.code, &response)
+
+			continue
+		}
+
+		if declared.Schema == nil || matchSchema(declared.Schema, response.Schema) {
+			continue
+		}
+
+		err = fmt.Errorf("%s: responds %d with %s, which is not the annotated schema", handler, used.code, used.typ)
+		if parser.Strict {
+			return err
+		}
+
+		parser.debug.Printf("warning: %s\n", err)
+	}
+
+	return nil
+}
+
+// matchSchema reports whether the schema inferred from a c.JSON argument is the annotated schema, the base of
+// a composed schema like Response{data=User} or one of the alternatives of a oneOf.
+func matchSchema(annotated, inferred *spec.Schema) bool {
+	if sameSchema(annotated, inferred) {
+		return true
+	}
+
+	if len(annotated.AllOf) > 0 && sameSchema(&annotated.AllOf[0], inferred) {
+		return true
+	}
+
+	for i := range annotated.OneOf {
+		if matchSchema(&annotated.OneOf[i], inferred) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sameSchema(a, b *spec.Schema) bool {
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+
+	return string(aJSON) == string(bJSON)
+}
+
+// handlerResponses walks the body of decl, and of the gin handlers it returns, for the c.JSON calls whose status
+// is an int literal or a net/http constant, in the order of the status codes. The type of a body is resolved for a
+// literal, a composite literal or a variable declared in the body with its type, other bodies like the result of a
+// call are returned with their source.
+func handlerResponses(astFile *ast.File, decl *ast.FuncDecl) []usedResponse {
+	var responses []usedResponse
+
+	contexts := ginContexts(astFile, decl)
+	if len(contexts) == 0 {
+		return responses
+	}
+
+	vars := localVarTypes(decl.Body)
+	seen := make(map[usedResponse]bool)
+
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) < 2 {
+			return true
+		}
+
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !ginJSONWriters[selector.Sel.Name] {
+			return true
+		}
+
+		receiver, ok := selector.X.(*ast.Ident)
+		if !ok || !contexts[receiver.Name] {
+			return true
+		}
+
+		code, ok := statusCode(astFile, call.Args[0])
+		if !ok {
+			return true
+		}
+
+		// c.JSONP(code, obj), the body is the last argument of every writer
+		body := call.Args[len(call.Args)-1]
+
+		response := usedResponse{code: code, typ: responseType(astFile, body, vars)}
+		if response.typ == "" {
+			response.expr = types.ExprString(body)
+		}
+
+		if !seen[response] {
+			seen[response] = true
+			responses = append(responses, response)
+		}
+
+		return true
+	})
+
+	sort.SliceStable(responses, func(i, j int) bool {
+		return responses[i].code < responses[j].code
+	})
+
+	return responses
+}
+
+// statusCode returns the value of an int literal or a net/http status constant.
+func statusCode(astFile *ast.File, expr ast.Expr) (int, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT {
+			return 0, false
+		}
+
+		code, err := strconv.Atoi(e.Value)
+
+		return code, err == nil
+	case *ast.SelectorExpr:
+		if !isQualifiedType(astFile, e, httpImportPath, e.Sel.Name) {
+			return 0, false
+		}
+
+		code, ok := httpStatusCodes[e.Sel.Name]
+
+		return code, ok
+	}
+
+	return 0, false
+}
+
+// responseType returns the type of the body expr in the syntax of a @Success comment, empty when unknown.
+func responseType(astFile *ast.File, expr ast.Expr, vars map[string]ast.Expr) string {
+	var typ ast.Expr
+
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.STRING:
+			return "string"
+		case token.INT:
+			return "int"
+		case token.FLOAT:
+			return "number"
+		}
+
+		return ""
+	case *ast.Ident:
+		typ = vars[e.Name]
+	default:
+		typ = valueType(expr)
+	}
+
+	if typ == nil {
+		return ""
+	}
+
+	return typeString(astFile, typ)
+}
+
+// typeString writes typ in the syntax of a @Success comment, without pointers and with gin.H as a map.
+func typeString(astFile *ast.File, typ ast.Expr) string {
+	switch t := typ.(type) {
+	case *ast.StarExpr:
+		return typeString(astFile, t.X)
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return ""
+		}
+
+		elem := typeString(astFile, t.Elt)
+		if elem == "" {
+			return ""
+		}
+
+		return "[]" + elem
+	case *ast.MapType:
+		value := typeString(astFile, t.Value)
+		if value == "" {
+			return ""
+		}
+
+		return "map[" + types.ExprString(t.Key) + "]" + value
+	case *ast.InterfaceType:
+		return INTERFACE
+	case *ast.StructType, *ast.FuncType, *ast.ChanType:
+		return ""
+	}
+
+	if isQualifiedType(astFile, typ, ginImportPath, "H") {
+		return "map[string]" + INTERFACE
+	}
+
+	return types.ExprString(typ)
+}
@@ -0,0 +1,126 @@
+package swag
+
+import (
+	goparser "go/parser"
+	"go/token"
+	"net/http"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+const inferResponsesSrc = `package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/example/app/model"
+)
+
+func GetUser(c *gin.Context) {
+	user := &model.User{}
+	if c.Param("id") == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "missing id"})
+		return
+	}
+
+	var users []*model.User
+	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, user)
+	c.IndentedJSON(206, users)
+	c.JSONP(http.StatusTeapot, "teapot")
+	c.JSON(http.StatusNotFound, model.Error{Message: "not found"})
+	c.JSON(http.StatusInternalServerError, loadError())
+	c.JSON(statusCode, user)
+}
+
+func GetOrder(c *gin.Context) {
+	resp, err := svc.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusOK, model.Error{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func NewListUsers() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.PureJSON(http.StatusCreated, map[string][]model.User{})
+	}
+}
+`
+
+func TestHandlerResponses(t *testing.T) {
+	t.Parallel()
+
+	astFile, err := goparser.ParseFile(token.NewFileSet(), "api.go", inferResponsesSrc, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []usedResponse{
+		{code: 200, typ: "model.User"},
+		{code: 206, typ: "[]model.User"},
+		{code: 400, typ: "map[string]interface{}"},
+		{code: 404, typ: "model.Error"},
+		{code: 418, typ: "string"},
+		{code: 500, expr: "loadError()"},
+	}, handlerResponses(astFile, findFuncDecl(astFile, "GetUser")))
+
+	assert.Equal(t, []usedResponse{
+		{code: 200, typ: "model.Error"},
+		{code: 200, expr: "resp"},
+	}, handlerResponses(astFile, findFuncDecl(astFile, "GetOrder")))
+
+	assert.Equal(t, []usedResponse{{code: 201, typ: "map[string][]model.User"}},
+		handlerResponses(astFile, findFuncDecl(astFile, "NewListUsers")))
+}
+
+func TestHTTPStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, http.StatusEarlyHints, httpStatusCodes["StatusEarlyHints"])
+	assert.Equal(t, http.StatusOK, httpStatusCodes["StatusOK"])
+	assert.Equal(t, http.StatusMultiStatus, httpStatusCodes["StatusMultiStatus"])
+	assert.Equal(t, http.StatusNonAuthoritativeInfo, httpStatusCodes["StatusNonAuthoritativeInfo"])
+	assert.Equal(t, http.StatusTeapot, httpStatusCodes["StatusTeapot"])
+	assert.Equal(t, http.StatusHTTPVersionNotSupported, httpStatusCodes["StatusHTTPVersionNotSupported"])
+	assert.Equal(t, http.StatusRequestURITooLong, httpStatusCodes["StatusRequestURITooLong"])
+}
+
+func TestMatchSchema(t *testing.T) {
+	t.Parallel()
+
+	user := spec.RefSchema("#/definitions/model.User")
+
+	assert.True(t, matchSchema(spec.RefSchema("#/definitions/model.User"), user))
+	assert.False(t, matchSchema(spec.RefSchema("#/definitions/model.Error"), user))
+	assert.False(t, matchSchema(spec.ArrayProperty(spec.RefSchema("#/definitions/model.User")), user))
+	assert.True(t, matchSchema(spec.ComposedSchema(*spec.RefSchema("#/definitions/model.User"), *spec.StringProperty()), user))
+	assert.True(t, matchSchema(&spec.Schema{SchemaProps: spec.SchemaProps{
+		OneOf: []spec.Schema{*spec.RefSchema("#/definitions/model.Error"), *spec.RefSchema("#/definitions/model.User")},
+	}}, user))
+}
+
+func TestTypeString(t *testing.T) {
+	t.Parallel()
+
+	astFile, err := goparser.ParseFile(token.NewFileSet(), "api.go", inferResponsesSrc, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	for src, want := range map[string]string{
+		"*model.User":            "model.User",
+		"[]*model.User":          "[]model.User",
+		"map[string]*model.User": "map[string]model.User",
+		"gin.H":                  "map[string]interface{}",
+		"[]gin.H":                "[]map[string]interface{}",
+		"interface{}":            "interface{}",
+		"Page[model.User]":       "Page[model.User]",
+		"[2]model.User":          "",
+		"struct{ ID int }":       "",
+	} {
+		expr, err := goparser.ParseExpr(src)
+		assert.NoError(t, err)
+		assert.Equal(t, want, typeString(astFile, expr), src)
+	}
+}
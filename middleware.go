@@ -0,0 +1,229 @@
+package swag
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Middleware references a gin middleware declared by a @Middleware or @tag.middleware comment,
+// e.g. auth.RequireAdmin or auth.RequireRole("admin").
+type Middleware struct {
+	// Package is the package qualifier as written, empty for an unqualified name
+	Package string
+
+	// ImportPath is the import path Package resolves to in the annotated file
+	ImportPath string
+
+	// Name is the referenced function or variable
+	Name string
+
+	// Call whether the middleware is built by calling Name with Args
+	Call bool
+
+	// Args holds the source of each call argument
+	Args []string
+
+	// ArgImports maps the package qualifiers used in Args, like roles in roles.Admin, to their import paths
+	ArgImports map[string]string
+}
+
+// String returns the middleware as written in the annotation.
+func (mw Middleware) String() string {
+	name := mw.Name
+	if mw.Package != "" {
+		name = mw.Package + "." + name
+	}
+
+	if mw.Call {
+		name += "(" + strings.Join(mw.Args, ", ") + ")"
+	}
+
+	return name
+}
+
+// ParseMiddlewareComment parses comment for given `middleware` comment string,
+// e.g. @Middleware auth.RequireAdmin, audit.Log.
+func (operation *Operation) ParseMiddlewareComment(commentLine string, astFile *ast.File) error {
+	middlewares, err := parseMiddlewares(commentLine)
+	if err != nil {
+		return err
+	}
+
+	if astFile != nil {
+		err = resolveMiddlewareImports(middlewares, astFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	operation.Middlewares = append(operation.Middlewares, middlewares...)
+
+	return nil
+}
+
+// parseMiddlewares parses a comma separated list of middleware expressions.
+func parseMiddlewares(commentLine string) ([]Middleware, error) {
+	commentLine = strings.TrimSpace(commentLine)
+	if commentLine == "" {
+		return nil, fmt.Errorf("middleware annotation needs at least one middleware")
+	}
+
+	// wrap the list in a call so commas nested in arguments stay where they belong
+	src := "_(" + commentLine + ")"
+
+	expr, err := goparser.ParseExpr(src)
+	if err != nil {
+		return nil, fmt.Errorf("can not parse middleware comment \"%s\": %s", commentLine, err)
+	}
+
+	var middlewares []Middleware
+
+	for _, arg := range expr.(*ast.CallExpr).Args {
+		middleware, err := parseMiddlewareExpr(src, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		middlewares = append(middlewares, middleware)
+	}
+
+	return middlewares, nil
+}
+
+func parseMiddlewareExpr(src string, expr ast.Expr) (Middleware, error) {
+	var middleware Middleware
+
+	if call, ok := expr.(*ast.CallExpr); ok {
+		middleware.Call = true
+		for _, arg := range call.Args {
+			middleware.Args = append(middleware.Args, src[arg.Pos()-1:arg.End()-1])
+		}
+
+		expr = call.Fun
+	}
+
+	switch fun := expr.(type) {
+	case *ast.Ident:
+		middleware.Name = fun.Name
+	case *ast.SelectorExpr:
+		pkg, ok := fun.X.(*ast.Ident)
+		if !ok {
+			return middleware, fmt.Errorf("unsupported middleware %s", src[expr.Pos()-1:expr.End()-1])
+		}
+
+		middleware.Package = pkg.Name
+		middleware.Name = fun.Sel.Name
+	default:
+		return middleware, fmt.Errorf("unsupported middleware %s", src[expr.Pos()-1:expr.End()-1])
+	}
+
+	return middleware, nil
+}
+
+// resolveMiddlewareImports resolves the package qualifier of each middleware against the imports of astFile.
+func resolveMiddlewareImports(middlewares []Middleware, astFile *ast.File) error {
+	for i := range middlewares {
+		if middlewares[i].Package == "" {
+			continue
+		}
+
+		importPath, ok := findImportPath(astFile, middlewares[i].Package)
+		if !ok {
+			return fmt.Errorf("can not find import of package %s for middleware %s", middlewares[i].Package, middlewares[i])
+		}
+
+		middlewares[i].ImportPath = importPath
+	}
+
+	for i := range middlewares {
+		for _, pkgName := range argPackages(middlewares[i].Args) {
+			// an unknown qualifier is a local of the argument, like c in func(c *gin.Context) { c.Next() }
+			importPath, ok := findImportPath(astFile, pkgName)
+			if !ok {
+				continue
+			}
+
+			if middlewares[i].ArgImports == nil {
+				middlewares[i].ArgImports = make(map[string]string)
+			}
+
+			middlewares[i].ArgImports[pkgName] = importPath
+		}
+	}
+
+	return nil
+}
+
+// argPackages returns the qualifiers of the selectors pkg.Name in the call arguments args.
+func argPackages(args []string) []string {
+	var pkgNames []string
+
+	for _, arg := range args {
+		expr, err := goparser.ParseExpr(arg)
+		if err != nil {
+			continue
+		}
+
+		ast.Inspect(expr, func(node ast.Node) bool {
+			if selector, ok := node.(*ast.SelectorExpr); ok {
+				if pkg, ok := selector.X.(*ast.Ident); ok {
+					pkgNames = append(pkgNames, pkg.Name)
+				}
+			}
+
+			return true
+		})
+	}
+
+	return pkgNames
+}
+
+// findImportPath returns the import path astFile refers to by the package name pkgName.
+func findImportPath(astFile *ast.File, pkgName string) (string, bool) {
+	for _, imp := range astFile.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		var name string
+		if imp.Name != nil {
+			name = imp.Name.Name
+		} else if pkgName, ok := defaultModuleResolver.PackageName(importPath); ok {
+			name = pkgName
+		} else {
+			name = importPackageName(importPath)
+		}
+
+		if name == pkgName {
+			return importPath, true
+		}
+	}
+
+	return "", false
+}
+
+// versionSuffixPattern matches the major version element of a module path, e.g. v4 in github.com/labstack/echo/v4.
+var versionSuffixPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// importPackageName guesses the package name of importPath without loading it, skipping a major
+// version suffix and a gopkg.in version, e.g. echo for github.com/labstack/echo/v4 and yaml for gopkg.in/yaml.v3.
+// It is the name jen gives the import in generated files, and the fallback of findImportPath for a package
+// whose source can not be found.
+func importPackageName(importPath string) string {
+	name := path.Base(importPath)
+	if versionSuffixPattern.MatchString(name) && path.Dir(importPath) != "." {
+		name = path.Base(path.Dir(importPath))
+	}
+
+	if i := strings.Index(name, ".v"); i > 0 && strings.HasPrefix(importPath, "gopkg.in/") {
+		name = name[:i]
+	}
+
+	return strings.TrimPrefix(name, "go-")
+}
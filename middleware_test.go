@@ -0,0 +1,84 @@
+package swag
+
+import (
+	goparser "go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMiddlewares(t *testing.T) {
+	t.Parallel()
+
+	middlewares, err := parseMiddlewares(`auth.RequireAdmin, audit.Log, auth.RequireRole("admin", "root"), localMiddleware`)
+	assert.NoError(t, err)
+	assert.Equal(t, []Middleware{
+		{Package: "auth", Name: "RequireAdmin"},
+		{Package: "audit", Name: "Log"},
+		{Package: "auth", Name: "RequireRole", Call: true, Args: []string{`"admin"`, `"root"`}},
+		{Name: "localMiddleware"},
+	}, middlewares)
+	assert.Equal(t, `auth.RequireRole("admin", "root")`, middlewares[2].String())
+
+	_, err = parseMiddlewares("")
+	assert.Error(t, err)
+
+	_, err = parseMiddlewares("auth.RequireAdmin,")
+	assert.NoError(t, err)
+
+	_, err = parseMiddlewares("a.b.c")
+	assert.Error(t, err)
+
+	_, err = parseMiddlewares("func() {}")
+	assert.Error(t, err)
+}
+
+func TestParseMiddlewareComment(t *testing.T) {
+	t.Parallel()
+
+	src := `package api
+
+import (
+	"github.com/example/project/audit"
+	authz "github.com/example/project/auth"
+	"github.com/example/project/roles"
+)
+`
+	astFile, err := goparser.ParseFile(token.NewFileSet(), "api.go", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	operation := NewOperation(nil)
+	err = operation.ParseComment(`// @Middleware authz.RequireAdmin, audit.Log`, astFile)
+	assert.NoError(t, err)
+	assert.Equal(t, []Middleware{
+		{Package: "authz", ImportPath: "github.com/example/project/auth", Name: "RequireAdmin"},
+		{Package: "audit", ImportPath: "github.com/example/project/audit", Name: "Log"},
+	}, operation.Middlewares)
+
+	operation = NewOperation(nil)
+	err = operation.ParseComment(`// @Middleware authz.Require(roles.Admin, cfg.Role)`, astFile)
+	assert.NoError(t, err)
+	assert.Equal(t, []Middleware{{
+		Package:    "authz",
+		ImportPath: "github.com/example/project/auth",
+		Name:       "Require",
+		Call:       true,
+		Args:       []string{"roles.Admin", "cfg.Role"},
+		ArgImports: map[string]string{"roles": "github.com/example/project/roles"},
+	}}, operation.Middlewares)
+
+	operation = NewOperation(nil)
+	err = operation.ParseComment(`// @Middleware unknown.Log`, astFile)
+	assert.Error(t, err)
+}
+
+func TestImportPackageName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "auth", importPackageName("example.com/app/auth"))
+	assert.Equal(t, "echo", importPackageName("github.com/labstack/echo/v4"))
+	assert.Equal(t, "chi", importPackageName("github.com/go-chi/chi/v5"))
+	assert.Equal(t, "yaml", importPackageName("gopkg.in/yaml.v3"))
+	assert.Equal(t, "http", importPackageName("net/http"))
+}
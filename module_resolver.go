@@ -0,0 +1,417 @@
+package swag
+
+import (
+	"fmt"
+	"go/build"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ModuleResolver computes the import path of a package directory from the go.mod of its module,
+// checked against the go.work workspace enclosing it. The go command is only run in GOPATH mode.
+type ModuleResolver struct {
+	mu sync.Mutex
+
+	// modules holds the module path by directory, empty for a directory without go.mod
+	modules map[string]string
+
+	// workspaces holds the module roots used by the go.work in a directory, nil without go.work
+	workspaces map[string][]string
+
+	// importPaths caches the resolved import path by directory
+	importPaths map[string]string
+
+	// requires holds the versions of the modules required by the go.mod in a directory
+	requires map[string]map[string]string
+
+	// packageNames caches the package clause by import path, empty for a package whose source is not found
+	packageNames map[string]string
+}
+
+// NewModuleResolver returns a new ModuleResolver.
+func NewModuleResolver() *ModuleResolver {
+	return &ModuleResolver{
+		modules:      make(map[string]string),
+		workspaces:   make(map[string][]string),
+		importPaths:  make(map[string]string),
+		requires:     make(map[string]map[string]string),
+		packageNames: make(map[string]string),
+	}
+}
+
+// defaultModuleResolver is shared by the parser and the router generator.
+var defaultModuleResolver = NewModuleResolver()
+
+// GetPackageName returns the import path of the package in searchDir.
+func GetPackageName(searchDir string) (string, error) {
+	return defaultModuleResolver.ImportPath(searchDir)
+}
+
+// ImportPath returns the import path of the package in dir.
+func (r *ModuleResolver) ImportPath(dir string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if importPath, ok := r.importPaths[dir]; ok {
+		return importPath, nil
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	root, modulePath, err := r.findModule(absDir)
+	if err != nil {
+		return "", err
+	}
+
+	var importPath string
+	if root == "" || os.Getenv("GO111MODULE") == "off" {
+		importPath, err = goListImportPath(dir)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		err = r.checkWorkspace(absDir, root, modulePath)
+		if err != nil {
+			return "", err
+		}
+
+		rel, err := filepath.Rel(root, absDir)
+		if err != nil {
+			return "", err
+		}
+
+		importPath = modulePath
+		if rel != "." {
+			importPath += "/" + filepath.ToSlash(rel)
+		}
+	}
+
+	r.importPaths[dir] = importPath
+
+	return importPath, nil
+}
+
+// PackageName returns the name in the package clause of the package importPath, looked up in the modules
+// resolved so far, their vendor directories and requirements in the module cache, and the standard library.
+func (r *ModuleResolver) PackageName(importPath string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name, ok := r.packageNames[importPath]
+	if !ok {
+		for _, dir := range r.packageDirs(importPath) {
+			name = readPackageClause(dir)
+			if name != "" {
+				break
+			}
+		}
+
+		r.packageNames[importPath] = name
+	}
+
+	return name, name != ""
+}
+
+// packageDirs returns the directories the source of the package importPath may be in.
+func (r *ModuleResolver) packageDirs(importPath string) []string {
+	var dirs []string
+
+	for root, modulePath := range r.modules {
+		if modulePath == "" {
+			continue
+		}
+
+		if rel, ok := trimModulePath(importPath, modulePath); ok {
+			dirs = append(dirs, filepath.Join(root, rel))
+		}
+
+		dirs = append(dirs, filepath.Join(root, "vendor", filepath.FromSlash(importPath)))
+
+		for requiredPath, version := range r.moduleRequires(root) {
+			if rel, ok := trimModulePath(importPath, requiredPath); ok {
+				dirs = append(dirs, filepath.Join(moduleCacheDir(), escapeModulePath(requiredPath)+"@"+version, rel))
+			}
+		}
+	}
+
+	if !strings.Contains(strings.Split(importPath, "/")[0], ".") {
+		dirs = append(dirs, filepath.Join(build.Default.GOROOT, "src", filepath.FromSlash(importPath)))
+	}
+
+	return dirs
+}
+
+// moduleRequires returns the required module versions of the go.mod in root.
+func (r *ModuleResolver) moduleRequires(root string) map[string]string {
+	requires, ok := r.requires[root]
+	if !ok {
+		requires = make(map[string]string)
+
+		content, err := os.ReadFile(filepath.Join(root, "go.mod"))
+		if err == nil {
+			for _, require := range parseModDirectives(content, "require") {
+				fields := strings.Fields(require)
+				if len(fields) == 2 {
+					requires[unquoteModPath(fields[0])] = fields[1]
+				}
+			}
+		}
+
+		r.requires[root] = requires
+	}
+
+	return requires
+}
+
+// trimModulePath returns the directory of the package importPath relative to the root of the module modulePath.
+func trimModulePath(importPath, modulePath string) (string, bool) {
+	if importPath == modulePath {
+		return ".", true
+	}
+
+	if !strings.HasPrefix(importPath, modulePath+"/") {
+		return "", false
+	}
+
+	return filepath.FromSlash(strings.TrimPrefix(importPath, modulePath+"/")), true
+}
+
+func moduleCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+
+	return filepath.Join(filepath.SplitList(build.Default.GOPATH)[0], "pkg", "mod")
+}
+
+// escapeModulePath escapes modulePath like the module cache does, e.g. github.com/!clover!o!s/swag-gin
+// for github.com/CloverOS/swag-gin.
+func escapeModulePath(modulePath string) string {
+	var escaped strings.Builder
+
+	for _, r := range modulePath {
+		if 'A' <= r && r <= 'Z' {
+			escaped.WriteByte('!')
+			r += 'a' - 'A'
+		}
+
+		escaped.WriteRune(r)
+	}
+
+	return filepath.FromSlash(escaped.String())
+}
+
+// readPackageClause returns the package name of the first non test Go file in dir, empty when there is none.
+func readPackageClause(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		astFile, err := goparser.ParseFile(token.NewFileSet(), filepath.Join(dir, name), nil, goparser.PackageClauseOnly)
+		if err == nil {
+			return astFile.Name.Name
+		}
+	}
+
+	return ""
+}
+
+// findModule returns the root directory and path of the module containing dir, empty without go.mod.
+func (r *ModuleResolver) findModule(dir string) (string, string, error) {
+	for ; ; dir = filepath.Dir(dir) {
+		modulePath, ok := r.modules[dir]
+		if !ok {
+			content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+			if err != nil && !os.IsNotExist(err) {
+				return "", "", err
+			}
+
+			if err == nil {
+				modulePath = parseModulePath(content)
+				if modulePath == "" {
+					return "", "", fmt.Errorf("%s has no module directive", filepath.Join(dir, "go.mod"))
+				}
+			}
+
+			r.modules[dir] = modulePath
+		}
+
+		if modulePath != "" {
+			return dir, modulePath, nil
+		}
+
+		if filepath.Dir(dir) == dir {
+			return "", "", nil
+		}
+	}
+}
+
+// checkWorkspace makes sure the module at root is used by the go.work enclosing dir, like the go command does.
+func (r *ModuleResolver) checkWorkspace(dir, root, modulePath string) error {
+	workFile := os.Getenv("GOWORK")
+	if workFile == "off" {
+		return nil
+	}
+
+	if workFile == "" {
+		for d := dir; ; d = filepath.Dir(d) {
+			_, err := os.Stat(filepath.Join(d, "go.work"))
+			if err == nil {
+				workFile = filepath.Join(d, "go.work")
+
+				break
+			}
+
+			if !os.IsNotExist(err) {
+				return err
+			}
+
+			if filepath.Dir(d) == d {
+				return nil
+			}
+		}
+	}
+
+	uses, ok := r.workspaces[workFile]
+	if !ok {
+		content, err := os.ReadFile(workFile)
+		if err != nil {
+			return err
+		}
+
+		for _, use := range parseWorkUses(content) {
+			if !filepath.IsAbs(use) {
+				use = filepath.Join(filepath.Dir(workFile), use)
+			}
+
+			uses = append(uses, filepath.Clean(use))
+		}
+
+		r.workspaces[workFile] = uses
+	}
+
+	for _, use := range uses {
+		if use == root {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("directory %s is contained in module %s, which is not one of the workspace modules listed in %s", dir, modulePath, workFile)
+}
+
+// parseModulePath returns the path of the module directive in the content of a go.mod.
+func parseModulePath(content []byte) string {
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(stripModComment(line))
+		if len(fields) != 2 || fields[0] != "module" {
+			continue
+		}
+
+		if modulePath, err := strconv.Unquote(fields[1]); err == nil {
+			return modulePath
+		}
+
+		return fields[1]
+	}
+
+	return ""
+}
+
+// parseWorkUses returns the directories of the use directives in the content of a go.work.
+func parseWorkUses(content []byte) []string {
+	var uses []string
+	for _, use := range parseModDirectives(content, "use") {
+		uses = append(uses, unquoteModPath(use))
+	}
+
+	return uses
+}
+
+// parseModDirectives returns the arguments of the verb directives in the content of a go.mod or go.work,
+// one per line of a block.
+func parseModDirectives(content []byte, verb string) []string {
+	var (
+		directives []string
+		inBlock    bool
+	)
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(stripModComment(line))
+		if inBlock {
+			if line == ")" {
+				inBlock = false
+			} else if line != "" {
+				directives = append(directives, line)
+			}
+
+			continue
+		}
+
+		if line != verb && !strings.HasPrefix(line, verb+" ") && !strings.HasPrefix(line, verb+"\t") && !strings.HasPrefix(line, verb+"(") {
+			continue
+		}
+
+		rest := strings.TrimSpace(strings.TrimPrefix(line, verb))
+		if rest == "(" {
+			inBlock = true
+		} else if rest != "" {
+			directives = append(directives, rest)
+		}
+	}
+
+	return directives
+}
+
+func stripModComment(line string) string {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return line[:i]
+	}
+
+	return line
+}
+
+func unquoteModPath(path string) string {
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		return unquoted
+	}
+
+	return path
+}
+
+// goListImportPath asks the go command for the import path of the package in dir, used in GOPATH mode.
+func goListImportPath(dir string) (string, error) {
+	cmd := exec.Command("go", "list", "-f={{.ImportPath}}")
+	cmd.Dir = dir
+
+	var stdout, stderr strings.Builder
+
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("execute go list command, %s, stdout:%s, stderr:%s", err, stdout.String(), stderr.String())
+	}
+
+	outStr := strings.Split(stdout.String(), "\n")[0]
+	if strings.HasPrefix(outStr, "_") { // will shown like _/{GOPATH}/src/{YOUR_PACKAGE} when NOT enable GO MODULE.
+		outStr = strings.TrimPrefix(outStr, "_"+build.Default.GOPATH+"/src/")
+	}
+
+	return outStr, nil
+}
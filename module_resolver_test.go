@@ -0,0 +1,147 @@
+package swag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModulePath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "example.com/app", parseModulePath([]byte("// app\nmodule example.com/app // the app\n\ngo 1.20\n")))
+	assert.Equal(t, "example.com/quoted", parseModulePath([]byte(`module "example.com/quoted"`)))
+	assert.Equal(t, "", parseModulePath([]byte("go 1.20\n")))
+}
+
+func TestParseWorkUses(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"./app", "./lib", "../shared"}, parseWorkUses([]byte(`go 1.21
+
+use ./app
+use (
+	./lib // library
+	"../shared"
+)
+`)))
+	assert.Equal(t, []string{"./app", "./lib"}, parseWorkUses([]byte("use (\n\t./app\n\t./lib\n)\n")))
+}
+
+func TestModuleResolver_ImportPath(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFile := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, name)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0666))
+	}
+	writeFile("app/go.mod", "module example.com/app\n")
+	writeFile("app/internal/api/api.go", "package api\n")
+	writeFile("app/plugins/go.mod", "module example.com/plugins\n")
+	writeFile("app/plugins/auth/auth.go", "package auth\n")
+
+	resolver := NewModuleResolver()
+
+	importPath, err := resolver.ImportPath(filepath.Join(root, "app"))
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/app", importPath)
+
+	importPath, err = resolver.ImportPath(filepath.Join(root, "app", "internal", "api"))
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/app/internal/api", importPath)
+
+	importPath, err = resolver.ImportPath(filepath.Join(root, "app", "plugins", "auth"))
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/plugins/auth", importPath)
+}
+
+func TestModuleResolver_Workspace(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"go.work":           "go 1.21\n\nuse ./app\n",
+		"app/go.mod":        "module example.com/app\n",
+		"app/api/api.go":    "package api\n",
+		"other/go.mod":      "module example.com/other\n",
+		"other/api/api.go":  "package api\n",
+		"broken/go.mod":     "go 1.21\n",
+		"broken/api/api.go": "package api\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, name)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0666))
+	}
+
+	resolver := NewModuleResolver()
+
+	importPath, err := resolver.ImportPath(filepath.Join(root, "app", "api"))
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/app/api", importPath)
+
+	_, err = resolver.ImportPath(filepath.Join(root, "other", "api"))
+	assert.ErrorContains(t, err, "not one of the workspace modules")
+
+	_, err = resolver.ImportPath(filepath.Join(root, "broken", "api"))
+	assert.ErrorContains(t, err, "has no module directive")
+}
+
+func TestGetPackageName(t *testing.T) {
+	t.Parallel()
+
+	importPath, err := GetPackageName("testdata/simple/api")
+	assert.NoError(t, err)
+	assert.Equal(t, "github.com/CloverOS/swag-gin/testdata/simple/api", importPath)
+}
+
+func TestModuleResolver_PackageName(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"app/go.mod":                        "module example.com/app\n",
+		"app/go-utils/utils.go":             "// Package utils ...\npackage utils\n",
+		"app/go-utils/utils_test.go":        "package utils_test\n",
+		"app/vendor/example.com/lib/x/x.go": "package xlib\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, name)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0666))
+	}
+
+	resolver := NewModuleResolver()
+	_, err := resolver.ImportPath(filepath.Join(root, "app"))
+	require.NoError(t, err)
+
+	name, ok := resolver.PackageName("example.com/app/go-utils")
+	assert.True(t, ok)
+	assert.Equal(t, "utils", name)
+
+	name, ok = resolver.PackageName("example.com/lib/x")
+	assert.True(t, ok)
+	assert.Equal(t, "xlib", name)
+
+	name, ok = resolver.PackageName("net/http")
+	assert.True(t, ok)
+	assert.Equal(t, "http", name)
+
+	_, ok = resolver.PackageName("example.com/app/missing")
+	assert.False(t, ok)
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, filepath.FromSlash("github.com/!clover!o!s/swag-gin"), escapeModulePath("github.com/CloverOS/swag-gin"))
+}
+
+func TestPkgNameMap(t *testing.T) {
+	PkgNameMap["testdata/pinned"] = "example.com/pinned"
+	defer delete(PkgNameMap, "testdata/pinned")
+
+	importPath, err := GetPackageName("testdata/pinned")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/pinned", importPath)
+}
@@ -0,0 +1,528 @@
+package openapi3
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	// ServersExtension holds the servers declared by @Server, or by @x-servers as a JSON array.
+	ServersExtension = "x-servers"
+
+	// NullableExtension marks a schema as nullable, set by the extensions struct tag.
+	NullableExtension = "x-nullable"
+
+	mimeJSON      = "application/json"
+	mimeForm      = "application/x-www-form-urlencoded"
+	mimeMultipart = "multipart/form-data"
+)
+
+// FromSwagger converts swagger into an OpenAPI 3.0 document: definitions become components/schemas, body
+// and formData parameters become the request body, and bodies are described for each media type of
+// @Accept and @Produce, application/json when none is declared.
+func FromSwagger(swagger *spec.Swagger) (*T, error) {
+	t := &T{
+		OpenAPI:      Version,
+		Info:         swagger.Info,
+		Paths:        make(map[string]*PathItem),
+		Security:     swagger.Security,
+		Tags:         swagger.Tags,
+		ExternalDocs: swagger.ExternalDocs,
+	}
+
+	if t.Info == nil {
+		t.Info = &spec.Info{}
+	}
+
+	servers, err := servers(swagger)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Servers = servers
+
+	for key, value := range swagger.Extensions {
+		if key == ServersExtension {
+			continue
+		}
+
+		if t.Extensions == nil {
+			t.Extensions = make(spec.Extensions)
+		}
+
+		t.Extensions[key] = value
+	}
+
+	components := &Components{}
+	for name, schema := range swagger.Definitions {
+		if components.Schemas == nil {
+			components.Schemas = make(map[string]spec.Schema)
+		}
+
+		components.Schemas[name] = *convertSchema(&schema)
+	}
+
+	for name, scheme := range swagger.SecurityDefinitions {
+		if components.SecuritySchemes == nil {
+			components.SecuritySchemes = make(map[string]*SecurityScheme)
+		}
+
+		converted, err := convertSecurityScheme(scheme)
+		if err != nil {
+			return nil, fmt.Errorf("security definition %s: %s", name, err)
+		}
+
+		components.SecuritySchemes[name] = converted
+	}
+
+	if components.Schemas != nil || components.SecuritySchemes != nil {
+		t.Components = components
+	}
+
+	if swagger.Paths == nil {
+		return t, nil
+	}
+
+	for path, item := range swagger.Paths.Paths {
+		converted := &PathItem{}
+
+		for _, param := range item.Parameters {
+			param, err := resolveParameter(swagger, param)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", path, err)
+			}
+
+			if param.In != "body" && param.In != "formData" {
+				converted.Parameters = append(converted.Parameters, convertParameter(param))
+			}
+		}
+
+		for _, method := range []struct {
+			operation *spec.Operation
+			converted **Operation
+		}{
+			{item.Get, &converted.Get},
+			{item.Put, &converted.Put},
+			{item.Post, &converted.Post},
+			{item.Delete, &converted.Delete},
+			{item.Options, &converted.Options},
+			{item.Head, &converted.Head},
+			{item.Patch, &converted.Patch},
+		} {
+			if method.operation == nil {
+				continue
+			}
+
+			operation, err := convertOperation(swagger, method.operation)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", path, err)
+			}
+
+			*method.converted = operation
+		}
+
+		t.Paths[path] = converted
+	}
+
+	return t, nil
+}
+
+// servers returns the servers of the x-servers extension, or else those of the schemes, host and base path.
+func servers(swagger *spec.Swagger) ([]Server, error) {
+	if value, ok := swagger.Extensions[ServersExtension]; ok {
+		var result []Server
+
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of servers", ServersExtension)
+		}
+
+		for _, item := range list {
+			server, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s must be an array of servers", ServersExtension)
+			}
+
+			url, _ := server["url"].(string)
+			description, _ := server["description"].(string)
+			if url == "" {
+				return nil, fmt.Errorf("a server of %s has no url", ServersExtension)
+			}
+
+			result = append(result, Server{URL: url, Description: description})
+		}
+
+		return result, nil
+	}
+
+	if swagger.Host == "" {
+		if swagger.BasePath == "" {
+			return nil, nil
+		}
+
+		return []Server{{URL: swagger.BasePath}}, nil
+	}
+
+	schemes := swagger.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"http"}
+	}
+
+	var result []Server
+	for _, scheme := range schemes {
+		result = append(result, Server{URL: strings.ToLower(scheme) + "://" + swagger.Host + swagger.BasePath})
+	}
+
+	return result, nil
+}
+
+func convertSecurityScheme(scheme *spec.SecurityScheme) (*SecurityScheme, error) {
+	converted := &SecurityScheme{Description: scheme.Description}
+
+	switch scheme.Type {
+	case "basic":
+		converted.Type = "http"
+		converted.Scheme = "basic"
+	case "apiKey":
+		converted.Type = "apiKey"
+		converted.Name = scheme.Name
+		converted.In = scheme.In
+	case "oauth2":
+		scopes := scheme.Scopes
+		if scopes == nil {
+			scopes = make(map[string]string)
+		}
+
+		converted.Type = "oauth2"
+		converted.Flows = &OAuthFlows{}
+		flow := &OAuthFlow{AuthorizationURL: scheme.AuthorizationURL, TokenURL: scheme.TokenURL, Scopes: scopes}
+
+		switch scheme.Flow {
+		case "implicit":
+			converted.Flows.Implicit = flow
+		case "password":
+			converted.Flows.Password = flow
+		case "application":
+			converted.Flows.ClientCredentials = flow
+		case "accessCode":
+			converted.Flows.AuthorizationCode = flow
+		default:
+			return nil, fmt.Errorf("unknown oauth2 flow %s", scheme.Flow)
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %s", scheme.Type)
+	}
+
+	return converted, nil
+}
+
+func convertOperation(swagger *spec.Swagger, operation *spec.Operation) (*Operation, error) {
+	converted := &Operation{
+		Tags:         operation.Tags,
+		Summary:      operation.Summary,
+		Description:  operation.Description,
+		ExternalDocs: operation.ExternalDocs,
+		OperationID:  operation.ID,
+		Responses:    make(map[string]*Response),
+		Deprecated:   operation.Deprecated,
+		Security:     operation.Security,
+		Extensions:   operation.Extensions,
+	}
+
+	consumes := operation.Consumes
+	if len(consumes) == 0 {
+		consumes = swagger.Consumes
+	}
+
+	produces := operation.Produces
+	if len(produces) == 0 {
+		produces = swagger.Produces
+	}
+
+	var form []spec.Parameter
+
+	for _, param := range operation.Parameters {
+		param, err := resolveParameter(swagger, param)
+		if err != nil {
+			return nil, err
+		}
+
+		switch param.In {
+		case "body":
+			converted.RequestBody = &RequestBody{
+				Description: param.Description,
+				Content:     content(consumes, []string{mimeJSON}, convertSchema(param.Schema)),
+				Required:    param.Required,
+			}
+		case "formData":
+			form = append(form, param)
+		default:
+			converted.Parameters = append(converted.Parameters, convertParameter(param))
+		}
+	}
+
+	if len(form) > 0 && converted.RequestBody == nil {
+		converted.RequestBody = formBody(form, consumes)
+	}
+
+	if operation.Responses != nil {
+		if operation.Responses.Default != nil {
+			converted.Responses["default"] = convertResponse(operation.Responses.Default, produces)
+		}
+
+		for code, response := range operation.Responses.StatusCodeResponses {
+			response := response
+			converted.Responses[strconv.Itoa(code)] = convertResponse(&response, produces)
+		}
+	}
+
+	return converted, nil
+}
+
+// resolveParameter returns the parameter a #/parameters reference points to.
+func resolveParameter(swagger *spec.Swagger, param spec.Parameter) (spec.Parameter, error) {
+	ref := param.Ref.String()
+	if ref == "" {
+		return param, nil
+	}
+
+	resolved, ok := swagger.Parameters[strings.TrimPrefix(ref, "#/parameters/")]
+	if !ok {
+		return param, fmt.Errorf("can not resolve parameter %s", ref)
+	}
+
+	return resolved, nil
+}
+
+// content returns schema for each media type of mimeTypes, or of fallback when there is none.
+func content(mimeTypes, fallback []string, schema *spec.Schema) map[string]MediaType {
+	if len(mimeTypes) == 0 {
+		mimeTypes = fallback
+	}
+
+	result := make(map[string]MediaType)
+	for _, mimeType := range mimeTypes {
+		result[mimeType] = MediaType{Schema: schema}
+	}
+
+	return result
+}
+
+// formBody turns formData parameters into an object schema, sent as a multipart form when it holds a file
+// or when the operation accepts only multipart forms.
+func formBody(params []spec.Parameter, consumes []string) *RequestBody {
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}, Properties: make(spec.SchemaProperties)}}
+	body := &RequestBody{}
+
+	multipart := false
+	for _, param := range params {
+		if param.Type == "file" || param.Items != nil && param.Items.Type == "file" {
+			multipart = true
+		}
+
+		property := simpleSchema(param.SimpleSchema, param.CommonValidations, param.Items)
+		property.Description = param.Description
+		schema.Properties[param.Name] = *property
+
+		if param.Required {
+			schema.Required = append(schema.Required, param.Name)
+			body.Required = true
+		}
+	}
+
+	var mimeTypes []string
+	for _, mimeType := range consumes {
+		if mimeType == mimeMultipart || mimeType == mimeForm && !multipart {
+			mimeTypes = append(mimeTypes, mimeType)
+		}
+	}
+
+	fallback := mimeForm
+	if multipart {
+		fallback = mimeMultipart
+	}
+
+	body.Content = content(mimeTypes, []string{fallback}, schema)
+
+	return body
+}
+
+// collectionStyles maps the collectionFormat of Swagger 2.0 to the style of a query parameter.
+var collectionStyles = map[string]string{
+	"csv":   "form",
+	"ssv":   "spaceDelimited",
+	"pipes": "pipeDelimited",
+	"multi": "form",
+}
+
+func convertParameter(param spec.Parameter) Parameter {
+	converted := Parameter{
+		Name:            param.Name,
+		In:              param.In,
+		Description:     param.Description,
+		Required:        param.Required || param.In == "path",
+		AllowEmptyValue: param.AllowEmptyValue,
+		Schema:          simpleSchema(param.SimpleSchema, param.CommonValidations, param.Items),
+		Example:         param.Example,
+	}
+
+	if param.Type == "array" {
+		format := param.CollectionFormat
+		if format == "" {
+			format = "csv"
+		}
+
+		explode := format == "multi"
+		if style, ok := collectionStyles[format]; ok && param.In == "query" {
+			converted.Style = style
+			converted.Explode = &explode
+		}
+	}
+
+	return converted
+}
+
+// simpleSchema returns the schema of a parameter, header or array item of Swagger 2.0.
+func simpleSchema(simple spec.SimpleSchema, validations spec.CommonValidations, items *spec.Items) *spec.Schema {
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Nullable:         simple.Nullable,
+		Format:           simple.Format,
+		Default:          simple.Default,
+		Maximum:          validations.Maximum,
+		ExclusiveMaximum: validations.ExclusiveMaximum,
+		Minimum:          validations.Minimum,
+		ExclusiveMinimum: validations.ExclusiveMinimum,
+		MaxLength:        validations.MaxLength,
+		MinLength:        validations.MinLength,
+		Pattern:          validations.Pattern,
+		MaxItems:         validations.MaxItems,
+		MinItems:         validations.MinItems,
+		UniqueItems:      validations.UniqueItems,
+		MultipleOf:       validations.MultipleOf,
+		Enum:             validations.Enum,
+	}}
+	schema.Example = simple.Example
+
+	if simple.Type != "" {
+		schema.Type = spec.StringOrArray{simple.Type}
+	}
+
+	if simple.Type == "file" {
+		schema.Type = spec.StringOrArray{"string"}
+		schema.Format = "binary"
+	}
+
+	if items != nil {
+		schema.Items = &spec.SchemaOrArray{Schema: simpleSchema(items.SimpleSchema, items.CommonValidations, items.Items)}
+	}
+
+	return schema
+}
+
+func convertResponse(response *spec.Response, produces []string) *Response {
+	converted := &Response{Description: response.Description}
+
+	if response.Schema != nil {
+		converted.Content = content(produces, []string{mimeJSON}, convertSchema(response.Schema))
+	}
+
+	for name, header := range response.Headers {
+		if converted.Headers == nil {
+			converted.Headers = make(map[string]Header)
+		}
+
+		converted.Headers[name] = Header{
+			Description: header.Description,
+			Schema:      simpleSchema(header.SimpleSchema, header.CommonValidations, header.Items),
+		}
+	}
+
+	return converted
+}
+
+// convertSchema returns a copy of schema referencing components/schemas, with x-nullable turned into nullable
+// and the file type into a binary string.
+func convertSchema(schema *spec.Schema) *spec.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	converted := *schema
+
+	if ref := schema.Ref.String(); strings.HasPrefix(ref, "#/definitions/") {
+		converted.Ref = spec.MustCreateRef("#/components/schemas/" + strings.TrimPrefix(ref, "#/definitions/"))
+	}
+
+	if len(schema.Type) == 1 && schema.Type[0] == "file" {
+		converted.Type = spec.StringOrArray{"string"}
+		converted.Format = "binary"
+	}
+
+	if nullable, ok := schema.Extensions[NullableExtension].(bool); ok {
+		converted.Nullable = nullable
+		converted.Extensions = make(spec.Extensions)
+
+		for key, value := range schema.Extensions {
+			if key != NullableExtension {
+				converted.Extensions[key] = value
+			}
+		}
+	}
+
+	if schema.Items != nil {
+		converted.Items = &spec.SchemaOrArray{Schema: convertSchema(schema.Items.Schema), Schemas: convertSchemas(schema.Items.Schemas)}
+	}
+
+	converted.AllOf = convertSchemas(schema.AllOf)
+	converted.OneOf = convertSchemas(schema.OneOf)
+	converted.AnyOf = convertSchemas(schema.AnyOf)
+	converted.Not = convertSchema(schema.Not)
+	converted.Properties = convertProperties(schema.Properties)
+	converted.PatternProperties = convertProperties(schema.PatternProperties)
+
+	if schema.AdditionalProperties != nil {
+		converted.AdditionalProperties = &spec.SchemaOrBool{
+			Allows: schema.AdditionalProperties.Allows,
+			Schema: convertSchema(schema.AdditionalProperties.Schema),
+		}
+	}
+
+	if schema.AdditionalItems != nil {
+		converted.AdditionalItems = &spec.SchemaOrBool{
+			Allows: schema.AdditionalItems.Allows,
+			Schema: convertSchema(schema.AdditionalItems.Schema),
+		}
+	}
+
+	return &converted
+}
+
+func convertSchemas(schemas []spec.Schema) []spec.Schema {
+	if schemas == nil {
+		return nil
+	}
+
+	converted := make([]spec.Schema, 0, len(schemas))
+	for i := range schemas {
+		converted = append(converted, *convertSchema(&schemas[i]))
+	}
+
+	return converted
+}
+
+func convertProperties(properties spec.SchemaProperties) spec.SchemaProperties {
+	if properties == nil {
+		return nil
+	}
+
+	converted := make(spec.SchemaProperties, len(properties))
+	for name, property := range properties {
+		property := property
+		converted[name] = *convertSchema(&property)
+	}
+
+	return converted
+}
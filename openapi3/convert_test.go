@@ -0,0 +1,255 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSwagger = `{
+    "swagger": "2.0",
+    "info": {"title": "Users", "version": "1.0", "x-logo": "logo.png"},
+    "host": "api.example.com",
+    "basePath": "/api/v1",
+    "schemes": ["https"],
+    "consumes": ["application/json"],
+    "produces": ["application/json"],
+    "paths": {
+        "/users": {
+            "post": {
+                "tags": ["users"],
+                "summary": "Create a user",
+                "operationId": "createUser",
+                "consumes": ["application/json", "application/xml"],
+                "parameters": [
+                    {"name": "user", "in": "body", "required": true, "description": "the user", "schema": {"$ref": "#/definitions/model.User"}},
+                    {"name": "X-Request-ID", "in": "header", "type": "string"},
+                    {"name": "session", "in": "cookie", "type": "string", "required": true}
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {"$ref": "#/definitions/model.User"},
+                        "headers": {"Location": {"type": "string", "description": "the user URL"}}
+                    },
+                    "default": {"description": "error", "schema": {"oneOf": [{"$ref": "#/definitions/model.Error"}, {"type": "string"}]}}
+                },
+                "security": [{"ApiKeyAuth": []}],
+                "x-codeSamples": [{"lang": "curl"}]
+            },
+            "get": {
+                "produces": ["application/json", "text/csv"],
+                "parameters": [
+                    {"name": "ids", "in": "query", "type": "array", "items": {"type": "integer"}},
+                    {"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"},
+                    {"name": "limit", "in": "query", "type": "integer", "minimum": 1, "maximum": 100, "default": 10}
+                ],
+                "responses": {"200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/model.User"}}}}
+            }
+        },
+        "/users/{id}/avatar": {
+            "put": {
+                "parameters": [
+                    {"name": "id", "in": "path", "type": "integer", "required": true},
+                    {"name": "avatar", "in": "formData", "type": "file", "required": true},
+                    {"name": "caption", "in": "formData", "type": "string"}
+                ],
+                "responses": {"204": {"description": "No Content"}}
+            }
+        }
+    },
+    "definitions": {
+        "model.User": {
+            "type": "object",
+            "required": ["name"],
+            "properties": {
+                "name": {"type": "string"},
+                "manager": {"$ref": "#/definitions/model.User", "x-nullable": true},
+                "emails": {"type": "array", "items": {"type": "string"}}
+            }
+        },
+        "model.Error": {"type": "object", "properties": {"error": {"type": "string"}}}
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {"type": "apiKey", "name": "Authorization", "in": "header"},
+        "BasicAuth": {"type": "basic"},
+        "OAuth2Password": {"type": "oauth2", "flow": "password", "tokenUrl": "https://example.com/token", "scopes": {"read": "read access"}}
+    }
+}`
+
+func convertTestSwagger(t *testing.T, modify func(swagger *spec.Swagger)) map[string]interface{} {
+	t.Helper()
+
+	var swagger spec.Swagger
+	assert.NoError(t, json.Unmarshal([]byte(testSwagger), &swagger))
+
+	if modify != nil {
+		modify(&swagger)
+	}
+
+	converted, err := FromSwagger(&swagger)
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(converted)
+	assert.NoError(t, err)
+
+	var doc map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &doc))
+
+	return doc
+}
+
+func jsonOf(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+
+	return string(data)
+}
+
+func TestFromSwagger(t *testing.T) {
+	t.Parallel()
+
+	doc := convertTestSwagger(t, nil)
+	paths := doc["paths"].(map[string]interface{})
+
+	assert.Equal(t, Version, doc["openapi"])
+	assert.JSONEq(t, `{"title": "Users", "version": "1.0", "x-logo": "logo.png"}`, jsonOf(t, doc["info"]))
+	assert.JSONEq(t, `[{"url": "https://api.example.com/api/v1"}]`, jsonOf(t, doc["servers"]))
+	assert.NotContains(t, doc, "swagger")
+	assert.NotContains(t, doc, "definitions")
+
+	assert.JSONEq(t, `{
+        "tags": ["users"],
+        "summary": "Create a user",
+        "operationId": "createUser",
+        "parameters": [
+            {"name": "X-Request-ID", "in": "header", "schema": {"type": "string"}},
+            {"name": "session", "in": "cookie", "required": true, "schema": {"type": "string"}}
+        ],
+        "requestBody": {
+            "description": "the user",
+            "required": true,
+            "content": {
+                "application/json": {"schema": {"$ref": "#/components/schemas/model.User"}},
+                "application/xml": {"schema": {"$ref": "#/components/schemas/model.User"}}
+            }
+        },
+        "responses": {
+            "201": {
+                "description": "Created",
+                "headers": {"Location": {"description": "the user URL", "schema": {"type": "string"}}},
+                "content": {"application/json": {"schema": {"$ref": "#/components/schemas/model.User"}}}
+            },
+            "default": {
+                "description": "error",
+                "content": {"application/json": {"schema": {"oneOf": [{"$ref": "#/components/schemas/model.Error"}, {"type": "string"}]}}}
+            }
+        },
+        "security": [{"ApiKeyAuth": []}],
+        "x-codeSamples": [{"lang": "curl"}]
+    }`, jsonOf(t, paths["/users"].(map[string]interface{})["post"]))
+
+	assert.JSONEq(t, `{
+        "parameters": [
+            {"name": "ids", "in": "query", "style": "form", "explode": false, "schema": {"type": "array", "items": {"type": "integer"}}},
+            {"name": "tags", "in": "query", "style": "form", "explode": true, "schema": {"type": "array", "items": {"type": "string"}}},
+            {"name": "limit", "in": "query", "schema": {"type": "integer", "minimum": 1, "maximum": 100, "default": 10}}
+        ],
+        "responses": {
+            "200": {
+                "description": "OK",
+                "content": {
+                    "application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/model.User"}}},
+                    "text/csv": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/model.User"}}}
+                }
+            }
+        }
+    }`, jsonOf(t, paths["/users"].(map[string]interface{})["get"]))
+
+	assert.JSONEq(t, `{
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+        "requestBody": {
+            "required": true,
+            "content": {
+                "multipart/form-data": {
+                    "schema": {
+                        "type": "object",
+                        "required": ["avatar"],
+                        "properties": {
+                            "avatar": {"type": "string", "format": "binary"},
+                            "caption": {"type": "string"}
+                        }
+                    }
+                }
+            }
+        },
+        "responses": {"204": {"description": "No Content"}}
+    }`, jsonOf(t, paths["/users/{id}/avatar"].(map[string]interface{})["put"]))
+
+	components := doc["components"].(map[string]interface{})
+	assert.JSONEq(t, `{
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+            "name": {"type": "string"},
+            "manager": {"$ref": "#/components/schemas/model.User", "nullable": true},
+            "emails": {"type": "array", "items": {"type": "string"}}
+        }
+    }`, jsonOf(t, components["schemas"].(map[string]interface{})["model.User"]))
+	assert.JSONEq(t, `{
+        "ApiKeyAuth": {"type": "apiKey", "name": "Authorization", "in": "header"},
+        "BasicAuth": {"type": "http", "scheme": "basic"},
+        "OAuth2Password": {"type": "oauth2", "flows": {"password": {"tokenUrl": "https://example.com/token", "scopes": {"read": "read access"}}}}
+    }`, jsonOf(t, components["securitySchemes"]))
+}
+
+func TestFromSwaggerServers(t *testing.T) {
+	t.Parallel()
+
+	doc := convertTestSwagger(t, func(swagger *spec.Swagger) {
+		swagger.AddExtension(ServersExtension, []interface{}{
+			map[string]interface{}{"url": "https://api.example.com/v1", "description": "production"},
+			map[string]interface{}{"url": "http://localhost:8080/v1"},
+		})
+	})
+	assert.JSONEq(t, `[{"url": "https://api.example.com/v1", "description": "production"}, {"url": "http://localhost:8080/v1"}]`,
+		jsonOf(t, doc["servers"]))
+	assert.NotContains(t, doc, ServersExtension)
+
+	doc = convertTestSwagger(t, func(swagger *spec.Swagger) {
+		swagger.Host = ""
+	})
+	assert.JSONEq(t, `[{"url": "/api/v1"}]`, jsonOf(t, doc["servers"]))
+
+	var swagger spec.Swagger
+	assert.NoError(t, json.Unmarshal([]byte(testSwagger), &swagger))
+
+	swagger.AddExtension(ServersExtension, "https://api.example.com")
+	_, err := FromSwagger(&swagger)
+	assert.EqualError(t, err, "x-servers must be an array of servers")
+
+	delete(swagger.Extensions, ServersExtension)
+	swagger.SecurityDefinitions["OAuth2"] = &spec.SecurityScheme{SecuritySchemeProps: spec.SecuritySchemeProps{Type: "oauth2", Flow: "device"}}
+	_, err = FromSwagger(&swagger)
+	assert.EqualError(t, err, "security definition OAuth2: unknown oauth2 flow device")
+}
+
+func TestConvertSchemaKeepsOriginal(t *testing.T) {
+	t.Parallel()
+
+	schema := spec.RefSchema("#/definitions/model.User")
+	schema.AddExtension(NullableExtension, true)
+	schema.AddExtension("x-order", 1)
+
+	converted := convertSchema(spec.ArrayProperty(schema))
+
+	assert.Equal(t, "#/definitions/model.User", schema.Ref.String())
+	assert.Equal(t, spec.Extensions{"x-nullable": true, "x-order": 1}, schema.Extensions)
+	assert.Equal(t, "#/components/schemas/model.User", converted.Items.Schema.Ref.String())
+	assert.True(t, converted.Items.Schema.Nullable)
+	assert.Equal(t, spec.Extensions{"x-order": 1}, converted.Items.Schema.Extensions)
+}
@@ -0,0 +1,157 @@
+// Package openapi3 converts the Swagger 2.0 document parsed by swag-gin into an OpenAPI 3.0 document.
+package openapi3
+
+import (
+	"encoding/json"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+// Version is the OpenAPI version of the converted documents.
+const Version = "3.0.3"
+
+// T is the root of an OpenAPI 3.0 document. Info, tags and schemas keep the types of go-openapi/spec,
+// whose JSON is the same in both versions.
+type T struct {
+	OpenAPI      string                      `json:"openapi"`
+	Info         *spec.Info                  `json:"info"`
+	Servers      []Server                    `json:"servers,omitempty"`
+	Paths        map[string]*PathItem        `json:"paths"`
+	Components   *Components                 `json:"components,omitempty"`
+	Security     []map[string][]string       `json:"security,omitempty"`
+	Tags         []spec.Tag                  `json:"tags,omitempty"`
+	ExternalDocs *spec.ExternalDocumentation `json:"externalDocs,omitempty"`
+	Extensions   spec.Extensions             `json:"-"`
+}
+
+// MarshalJSON writes the extensions next to the fields of the document.
+func (t T) MarshalJSON() ([]byte, error) {
+	type document T
+
+	return marshalWithExtensions(document(t), t.Extensions)
+}
+
+// Server is an URL the API is served at.
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components holds the schemas and security schemes referenced by the operations.
+type Components struct {
+	Schemas         map[string]spec.Schema     `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is a http, apiKey or oauth2 security scheme.
+type SecurityScheme struct {
+	Type        string      `json:"type"`
+	Description string      `json:"description,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	In          string      `json:"in,omitempty"`
+	Scheme      string      `json:"scheme,omitempty"`
+	Flows       *OAuthFlows `json:"flows,omitempty"`
+}
+
+// OAuthFlows are the flows of an oauth2 security scheme.
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+// OAuthFlow is an oauth2 flow.
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+// PathItem holds the operations of a path.
+type PathItem struct {
+	Get        *Operation  `json:"get,omitempty"`
+	Put        *Operation  `json:"put,omitempty"`
+	Post       *Operation  `json:"post,omitempty"`
+	Delete     *Operation  `json:"delete,omitempty"`
+	Options    *Operation  `json:"options,omitempty"`
+	Head       *Operation  `json:"head,omitempty"`
+	Patch      *Operation  `json:"patch,omitempty"`
+	Parameters []Parameter `json:"parameters,omitempty"`
+}
+
+// Operation is an API operation, whose body and form parameters are turned into a request body.
+type Operation struct {
+	Tags         []string                    `json:"tags,omitempty"`
+	Summary      string                      `json:"summary,omitempty"`
+	Description  string                      `json:"description,omitempty"`
+	ExternalDocs *spec.ExternalDocumentation `json:"externalDocs,omitempty"`
+	OperationID  string                      `json:"operationId,omitempty"`
+	Parameters   []Parameter                 `json:"parameters,omitempty"`
+	RequestBody  *RequestBody                `json:"requestBody,omitempty"`
+	Responses    map[string]*Response        `json:"responses"`
+	Deprecated   bool                        `json:"deprecated,omitempty"`
+	Security     []map[string][]string       `json:"security,omitempty"`
+	Extensions   spec.Extensions             `json:"-"`
+}
+
+// MarshalJSON writes the extensions next to the fields of the operation.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+
+	return marshalWithExtensions(operation(o), o.Extensions)
+}
+
+// Parameter is a path, query, header or cookie parameter.
+type Parameter struct {
+	Name            string       `json:"name"`
+	In              string       `json:"in"`
+	Description     string       `json:"description,omitempty"`
+	Required        bool         `json:"required,omitempty"`
+	AllowEmptyValue bool         `json:"allowEmptyValue,omitempty"`
+	Style           string       `json:"style,omitempty"`
+	Explode         *bool        `json:"explode,omitempty"`
+	Schema          *spec.Schema `json:"schema,omitempty"`
+	Example         interface{}  `json:"example,omitempty"`
+}
+
+// RequestBody is the body of an operation, in each accepted media type.
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+	Required    bool                 `json:"required,omitempty"`
+}
+
+// MediaType is the schema of a body in a media type.
+type MediaType struct {
+	Schema  *spec.Schema `json:"schema,omitempty"`
+	Example interface{}  `json:"example,omitempty"`
+}
+
+// Response is a response of an operation, in each produced media type.
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header is a header of a response.
+type Header struct {
+	Description string       `json:"description,omitempty"`
+	Schema      *spec.Schema `json:"schema,omitempty"`
+}
+
+func marshalWithExtensions(v interface{}, extensions spec.Extensions) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extensions) == 0 {
+		return data, err
+	}
+
+	ext, err := json.Marshal(extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	return swag.ConcatJSON(data, ext), nil
+}
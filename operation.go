@@ -21,6 +21,8 @@ import (
 type RouteProperties struct {
 	HTTPMethod string
 	Path       string
+	// RawPath is the path as annotated, which may carry catch-all markers like {path*}.
+	RawPath string
 }
 
 // Operation describes a single API operation on a path.
@@ -30,6 +32,8 @@ type Operation struct {
 	codeExampleFilesDir string
 	spec.Operation
 	RouterProperties []RouteProperties
+	Middlewares      []Middleware
+	BodyType         *ParamGoType
 }
 
 var mimeTypeAliases = map[string]string{
@@ -147,6 +151,8 @@ func (operation *Operation) ParseComment(comment string, astFile *ast.File) erro
 		return operation.ParseRouterComment(lineRemainder)
 	case securityAttr:
 		return operation.ParseSecurityComment(lineRemainder)
+	case middlewareAttr:
+		return operation.ParseMiddlewareComment(lineRemainder, astFile)
 	case deprecatedAttr:
 		operation.Deprecate()
 	case xCodeSamplesAttr:
@@ -274,8 +280,12 @@ func (operation *Operation) ParseParamComment(commentLine string, astFile *ast.F
 
 	param := createParameter(paramType, description, name, objectType, refType, required, enums, operation.parser.collectionFormatInQuery)
 
+	if paramType == "body" {
+		operation.BodyType = parseParamGoType(matches[3], astFile)
+	}
+
 	switch paramType {
-	case "path", "header":
+	case "path", "header", "cookie":
 		switch objectType {
 		case ARRAY:
 			if !IsPrimitiveType(refType) {
@@ -690,7 +700,7 @@ func parseMimeTypeList(mimeTypeList string, typeList *[]string, format string) e
 	return nil
 }
 
-var routerPattern = regexp.MustCompile(`^(/[\w./\-{}+:$]*)[[:blank:]]+\[(\w+)]`)
+var routerPattern = regexp.MustCompile(`^(/[\w./\-{}+*:$]*)[[:blank:]]+\[(\w+)]`)
 
 // ParseRouterComment parses comment for given `router` comment string.
 func (operation *Operation) ParseRouterComment(commentLine string) error {
@@ -700,7 +710,8 @@ func (operation *Operation) ParseRouterComment(commentLine string) error {
 	}
 
 	signature := RouteProperties{
-		Path:       matches[1],
+		Path:       SpecPath(matches[1]),
+		RawPath:    matches[1],
 		HTTPMethod: strings.ToUpper(matches[2]),
 	}
 
@@ -713,14 +724,18 @@ func (operation *Operation) ParseRouterComment(commentLine string) error {
 	return nil
 }
 
+// securityPairSepPattern separates the schemes of one security requirement, which are all required.
+var securityPairSepPattern = regexp.MustCompile(`\|\||&&`)
+
 // ParseSecurityComment parses comment for given `security` comment string.
+// Each comment is one requirement, alternative to the others, the schemes joined with && are all required.
 func (operation *Operation) ParseSecurityComment(commentLine string) error {
 	var (
 		securityMap    = make(map[string][]string)
 		securitySource = commentLine[strings.Index(commentLine, "@Security")+1:]
 	)
 
-	for _, securityOption := range strings.Split(securitySource, "||") {
+	for _, securityOption := range securityPairSepPattern.Split(securitySource, -1) {
 		securityOption = strings.TrimSpace(securityOption)
 
 		left, right := strings.Index(securityOption, "["), strings.Index(securityOption, "]")
@@ -804,7 +819,7 @@ func findTypeDef(importPath, typeName string) (*ast.TypeSpec, error) {
 	return nil, fmt.Errorf("type spec not found")
 }
 
-var responsePattern = regexp.MustCompile(`^([\w,]+)\s+([\w{}]+)\s+([\w\-.\\{}=,\[\s\]]+)\s*(".*)?`)
+var responsePattern = regexp.MustCompile(`^([\w,]+)\s+([\w{}]+)\s+([\w\-.\\{}=,|\[\s\]]+)\s*(".*)?`)
 
 // ResponseType{data1=Type1,data2=Type2}.
 var combinedPattern = regexp.MustCompile(`^([\w\-./\[\]]+){(.*)}$`)
@@ -814,6 +829,23 @@ func (operation *Operation) parseObjectSchema(refType string, astFile *ast.File)
 }
 
 func parseObjectSchema(parser *Parser, refType string, astFile *ast.File) (*spec.Schema, error) {
+	if alternatives := parseAlternatives(refType); len(alternatives) > 1 {
+		schema := &spec.Schema{}
+
+		for _, alternative := range alternatives {
+			alternativeSchema, err := parseObjectSchema(parser, alternative, astFile)
+			if err != nil {
+				return nil, err
+			}
+
+			if alternativeSchema != nil {
+				schema.OneOf = append(schema.OneOf, *alternativeSchema)
+			}
+		}
+
+		return schema, nil
+	}
+
 	switch {
 	case refType == NIL:
 		return nil, nil
@@ -886,6 +918,22 @@ func parseFields(s string) []string {
 	})
 }
 
+// parseAlternatives splits a type like model.Cat|model.Dog into its alternatives, ignoring | nested in {}.
+func parseAlternatives(s string) []string {
+	nestLevel := 0
+
+	return strings.FieldsFunc(s, func(char rune) bool {
+		switch char {
+		case '{':
+			nestLevel++
+		case '}':
+			nestLevel--
+		}
+
+		return char == '|' && nestLevel == 0
+	})
+}
+
 func parseCombinedObjectSchema(parser *Parser, refType string, astFile *ast.File) (*spec.Schema, error) {
 	matches := combinedPattern.FindStringSubmatch(refType)
 	if len(matches) != 3 {
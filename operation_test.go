@@ -156,10 +156,24 @@ func TestParseRouterCommentWithPlusSign(t *testing.T) {
 	err := operation.ParseComment(comment, nil)
 	assert.NoError(t, err)
 	assert.Len(t, operation.RouterProperties, 1)
-	assert.Equal(t, "/customer/get-wishlist/{proxy+}", operation.RouterProperties[0].Path)
+	assert.Equal(t, "/customer/get-wishlist/{proxy}", operation.RouterProperties[0].Path)
+	assert.Equal(t, "/customer/get-wishlist/{proxy+}", operation.RouterProperties[0].RawPath)
 	assert.Equal(t, "POST", operation.RouterProperties[0].HTTPMethod)
 }
 
+func TestParseRouterCommentWithCatchAll(t *testing.T) {
+	t.Parallel()
+
+	comment := `/@Router /files/{path*} [get]`
+	operation := NewOperation(nil)
+	err := operation.ParseComment(comment, nil)
+	assert.NoError(t, err)
+	assert.Len(t, operation.RouterProperties, 1)
+	assert.Equal(t, "/files/{path}", operation.RouterProperties[0].Path)
+	assert.Equal(t, "/files/{path*}", operation.RouterProperties[0].RawPath)
+	assert.Equal(t, "GET", operation.RouterProperties[0].HTTPMethod)
+}
+
 func TestParseRouterCommentWithDollarSign(t *testing.T) {
 	t.Parallel()
 
@@ -1313,6 +1327,27 @@ func TestParseParamCommentByID(t *testing.T) {
 	assert.Equal(t, expected, string(b))
 }
 
+func TestParseParamCommentByCookie(t *testing.T) {
+	t.Parallel()
+
+	comment := `@Param session cookie string true "Session ID"`
+	operation := NewOperation(nil)
+	err := operation.ParseComment(comment, nil)
+
+	assert.NoError(t, err)
+	b, _ := json.MarshalIndent(operation.Parameters, "", "    ")
+	expected := `[
+    {
+        "type": "string",
+        "description": "Session ID",
+        "name": "session",
+        "in": "cookie",
+        "required": true
+    }
+]`
+	assert.Equal(t, expected, string(b))
+}
+
 func TestParseParamCommentByQueryType(t *testing.T) {
 	t.Parallel()
 
@@ -2117,6 +2152,27 @@ func TestParseSecurityCommentOr(t *testing.T) {
 	})
 }
 
+func TestParseSecurityCommentAnd(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil)
+
+	err := operation.ParseComment(`@Security ApiKey && OAuth2[read]`, nil)
+	assert.NoError(t, err)
+	err = operation.ParseComment(`@Security Basic`, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []map[string][]string{
+		{
+			"ApiKey": {},
+			"OAuth2": {"read"},
+		},
+		{
+			"Basic": {},
+		},
+	}, operation.Security)
+}
+
 func TestParseMultiDescription(t *testing.T) {
 	t.Parallel()
 
@@ -2304,6 +2360,18 @@ func TestParseObjectSchema(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, schema, spec.MapProperty(nil))
 
+	schema, err = operation.parseObjectSchema("string|[]int", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &spec.Schema{SchemaProps: spec.SchemaProps{
+		OneOf: []spec.Schema{*PrimitiveSchema(STRING), *spec.ArrayProperty(PrimitiveSchema(INTEGER))},
+	}}, schema)
+
+	schema, err = operation.parseObjectSchema("any{data=string|int}", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, PrimitiveSchema(OBJECT).SetProperty("data", spec.Schema{SchemaProps: spec.SchemaProps{
+		OneOf: []spec.Schema{*PrimitiveSchema(STRING), *PrimitiveSchema(INTEGER)},
+	}}), schema)
+
 	_, err = operation.parseObjectSchema("map[string", nil)
 	assert.Error(t, err)
 
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
-	"go/build"
 	goparser "go/parser"
 	"go/token"
 	"io/ioutil"
@@ -14,13 +13,13 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/CloverOS/swag-gin/openapi3"
 	"github.com/KyleBanks/depth"
 	"github.com/go-openapi/spec"
 )
@@ -48,6 +47,7 @@ const (
 	summaryAttr             = "@summary"
 	deprecatedAttr          = "@deprecated"
 	securityAttr            = "@security"
+	middlewareAttr          = "@middleware"
 	titleAttr               = "@title"
 	conNameAttr             = "@contact.name"
 	conURLAttr              = "@contact.url"
@@ -64,6 +64,7 @@ const (
 	secPasswordAttr         = "@securitydefinitions.oauth2.password"
 	secAccessCodeAttr       = "@securitydefinitions.oauth2.accesscode"
 	tosAttr                 = "@termsofservice"
+	serverAttr              = "@server"
 	xCodeSamplesAttr        = "@x-codesamples"
 	scopeAttrPrefix         = "@scope."
 )
@@ -81,7 +82,10 @@ var (
 	// ErrSkippedField .swaggo specifies field should be skipped.
 	ErrSkippedField = errors.New("field is skipped by global overrides")
 
-	PkgNameMap = make(map[string]string)
+	// PkgNameMap caches the import path by search directory.
+	//
+	// Deprecated: it is the import path cache of the module resolver behind GetPackageName, use a ModuleResolver.
+	PkgNameMap = defaultModuleResolver.importPaths
 )
 
 var allMethod = map[string]struct{}{
@@ -150,6 +154,18 @@ type Parser struct {
 	// excludes excludes dirs and files in SearchDir
 	excludes map[string]struct{}
 
+	// tags to include or, prefixed with !, to exclude from the operations and the generated routers
+	tags map[string]struct{}
+
+	// parseExtension keeps only the operations declaring this x- extension
+	parseExtension string
+
+	// inferParams adds the parameters the gin handlers read but do not annotate
+	inferParams bool
+
+	// inferResponses adds the responses the gin handlers write with c.JSON but do not annotate
+	inferResponses bool
+
 	// debugging output goes here
 	debug Debugger
 
@@ -163,16 +179,44 @@ type Parser struct {
 	parseGoList bool
 
 	//  HandlerFunc for register router to gin web framework
-	HandlerFunc map[string]string
+	HandlerFunc map[RouteKey]string
 
-	// HandlerFuncModules
-	HandlerFuncModules map[string]string
+	// HandlerFuncModules holds the directory of the handler of each route
+	HandlerFuncModules map[RouteKey]string
 
-	// FilePathHandlerFunc
+	// FilePathHandlerFunc holds the absolute directory one level above the handler of each path, ending with a separator.
+	//
+	// Deprecated: the router directory depends on the RouterLayout, use HandlerFuncModules.
 	FilePathHandlerFunc map[string]string
 
-	//PkgName
+	// PkgName holds the name of the directory one level above the handler of each path.
+	//
+	// Deprecated: the router package depends on the RouterLayout, use HandlerFuncModules.
 	PkgName map[string]string
+
+	// HandlerSignatures holds how the generated routers obtain the handler of each route
+	HandlerSignatures map[RouteKey]HandlerSignature
+
+	// RoutePath holds the annotated router path, which may carry catch-all markers
+	RoutePath map[RouteKey]string
+
+	// Middlewares holds the gin middlewares declared by @Middleware for each route
+	Middlewares map[RouteKey][]Middleware
+
+	// BodyTypes holds the Go type of the body @Param of each route
+	BodyTypes map[RouteKey]*ParamGoType
+
+	// TagMiddlewares holds the gin middlewares declared by @tag.middleware for each tag
+	TagMiddlewares map[string][]Middleware
+
+	// RouteDeclarations holds every @Router of a handler, including those declaring a route twice
+	RouteDeclarations []RouteDeclaration
+}
+
+// RouteKey identifies a single route declared by a @Router comment.
+type RouteKey struct {
+	Method string
+	Path   string
 }
 
 // FieldParserFactory create FieldParser.
@@ -226,12 +270,18 @@ func New(options ...func(*Parser)) *Parser {
 		existSchemaNames:    make(map[string]*Schema),
 		toBeRenamedSchemas:  make(map[string]string),
 		excludes:            make(map[string]struct{}),
+		tags:                make(map[string]struct{}),
 		fieldParserFactory:  newTagBaseFieldParser,
 		Overrides:           make(map[string]string),
-		HandlerFunc:         make(map[string]string),
+		HandlerFunc:         make(map[RouteKey]string),
+		HandlerFuncModules:  make(map[RouteKey]string),
+		HandlerSignatures:   make(map[RouteKey]HandlerSignature),
 		FilePathHandlerFunc: make(map[string]string),
-		HandlerFuncModules:  make(map[string]string),
 		PkgName:             make(map[string]string),
+		RoutePath:           make(map[RouteKey]string),
+		Middlewares:         make(map[RouteKey][]Middleware),
+		BodyTypes:           make(map[RouteKey]*ParamGoType),
+		TagMiddlewares:      make(map[string][]Middleware),
 	}
 
 	for _, option := range options {
@@ -268,6 +318,32 @@ func SetExcludedDirsAndFiles(excludes string) func(*Parser) {
 	}
 }
 
+// SetTags sets the comma separated tags of the operations to parse, a tag prefixed with ! excludes its operations.
+func SetTags(include string) func(*Parser) {
+	return func(p *Parser) {
+		for _, tag := range strings.Split(include, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag != "" {
+				p.tags[tag] = struct{}{}
+			}
+		}
+	}
+}
+
+// SetParseExtension parses only the operations declaring the extension @x-parseExtension.
+func SetParseExtension(parseExtension string) func(*Parser) {
+	return func(p *Parser) {
+		p.parseExtension = parseExtension
+	}
+}
+
+// SetCollectionFormat sets the default collectionFormat of array query parameters.
+func SetCollectionFormat(collectionFormat string) func(*Parser) {
+	return func(p *Parser) {
+		p.collectionFormatInQuery = collectionFormat
+	}
+}
+
 // SetStrict sets whether swag should error or warn when it detects cases which are most likely user errors.
 func SetStrict(strict bool) func(*Parser) {
 	return func(p *Parser) {
@@ -318,7 +394,7 @@ func (parser *Parser) ParseAPIMultiSearchDir(searchDirs []string, mainAPIFile st
 	for _, searchDir := range searchDirs {
 		parser.debug.Printf("Generate general API Info, search dir:%s", searchDir)
 
-		packageDir, err := getPkgName(searchDir)
+		packageDir, err := GetPackageName(searchDir)
 		if err != nil {
 			parser.debug.Printf("warning: failed to get package name in dir: %s, error: %s", searchDir, err.Error())
 		}
@@ -354,7 +430,7 @@ func (parser *Parser) ParseAPIMultiSearchDir(searchDirs []string, mainAPIFile st
 			t.ResolveInternal = true
 			t.MaxDepth = parseDepth
 
-			pkgName, err := getPkgName(filepath.Dir(absMainAPIFilePath))
+			pkgName, err := GetPackageName(filepath.Dir(absMainAPIFilePath))
 			if err != nil {
 				return err
 			}
@@ -392,36 +468,6 @@ func (parser *Parser) ParseAPIMultiSearchDir(searchDirs []string, mainAPIFile st
 	return parser.checkOperationIDUniqueness()
 }
 
-func getPkgName(searchDir string) (string, error) {
-	s, exists := PkgNameMap[searchDir]
-	if exists {
-		return s, nil
-	}
-	cmd := exec.Command("go", "list", "-f={{.ImportPath}}")
-	cmd.Dir = searchDir
-
-	var stdout, stderr strings.Builder
-
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("execute go list command, %s, stdout:%s, stderr:%s", err, stdout.String(), stderr.String())
-	}
-
-	outStr, _ := stdout.String(), stderr.String()
-
-	if outStr[0] == '_' { // will shown like _/{GOPATH}/src/{YOUR_PACKAGE} when NOT enable GO MODULE.
-		outStr = strings.TrimPrefix(outStr, "_"+build.Default.GOPATH+"/src/")
-	}
-
-	f := strings.Split(outStr, "\n")
-
-	outStr = f[0]
-	PkgNameMap[searchDir] = outStr
-	return outStr, nil
-}
-
 // ParseGeneralAPIInfo parses general api info for given mainAPIFile path.
 func (parser *Parser) ParseGeneralAPIInfo(mainAPIFile string) error {
 	fileTree, err := goparser.ParseFile(token.NewFileSet(), mainAPIFile, nil, goparser.ParseComments)
@@ -443,6 +489,19 @@ func (parser *Parser) ParseGeneralAPIInfo(mainAPIFile string) error {
 		}
 	}
 
+	for tag, middlewares := range parser.TagMiddlewares {
+		for _, middleware := range middlewares {
+			if middleware.Package == "" {
+				return fmt.Errorf("middleware %s of tag %s must be qualified by its package", middleware, tag)
+			}
+		}
+
+		err = resolveMiddlewareImports(middlewares, fileTree)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -516,6 +575,18 @@ func parseGeneralAPIInfo(parser *Parser, comments []string) error {
 
 			tag.TagProps.Description = string(commentInfo)
 			replaceLastTag(parser.swagger.Tags, tag)
+		case "@tag.middleware":
+			if len(parser.swagger.Tags) == 0 {
+				return fmt.Errorf("%s needs to come after a @tag.name", attribute)
+			}
+
+			middlewares, err := parseMiddlewares(value)
+			if err != nil {
+				return err
+			}
+
+			tagName := parser.swagger.Tags[len(parser.swagger.Tags)-1].Name
+			parser.TagMiddlewares[tagName] = append(parser.TagMiddlewares[tagName], middlewares...)
 		case "@tag.docs.url":
 			tag := parser.swagger.Tags[len(parser.swagger.Tags)-1]
 			tag.TagProps.ExternalDocs = &spec.ExternalDocumentation{
@@ -543,6 +614,24 @@ func parseGeneralAPIInfo(parser *Parser, comments []string) error {
 
 		case "@query.collection.format":
 			parser.collectionFormatInQuery = value
+		case serverAttr:
+			fields := FieldsByAnySpace(value, 2)
+			if len(fields) == 0 {
+				return fmt.Errorf("%s needs an url", attribute)
+			}
+
+			server := map[string]interface{}{"url": fields[0]}
+			if len(fields) > 1 {
+				server["description"] = fields[1]
+			}
+
+			if parser.swagger.Extensions == nil {
+				parser.swagger.Extensions = make(map[string]interface{})
+			}
+
+			// Swagger 2.0 has no servers, they are kept for the openapi3 output type
+			servers, _ := parser.swagger.Extensions[openapi3.ServersExtension].([]interface{})
+			parser.swagger.Extensions[openapi3.ServersExtension] = append(servers, server)
 		default:
 			prefixExtension := "@x-"
 			// Prefix extension + 1 char + 1 space  + 1 char
@@ -592,6 +681,16 @@ func parseGeneralAPIInfo(parser *Parser, comments []string) error {
 		previousAttribute = attribute
 	}
 
+	// the general tags follow the tags filter of the operations
+	tags := parser.swagger.Tags[:0]
+	for _, tag := range parser.swagger.Tags {
+		if parser.matchTagList([]string{tag.Name}) {
+			tags = append(tags, tag)
+		}
+	}
+
+	parser.swagger.Tags = tags
+
 	return nil
 }
 
@@ -829,22 +928,29 @@ func (parser *Parser) ParseRouterAPIInfo(fileName string, astFile *ast.File) err
 		}
 		astDeclaration, ok := astDescription.(*ast.FuncDecl)
 		if ok && astDeclaration.Doc != nil && astDeclaration.Doc.List != nil {
+			// filtered operations are left out of the docs, so of the generated routers and resource.go too
+			if !parser.matchTags(astDeclaration.Doc.List) || !matchExtension(parser.parseExtension, astDeclaration.Doc.List) {
+				continue
+			}
+
 			var handlerFunName string
-			if astDeclaration.Recv != nil {
-				if len(astDeclaration.Recv.List) > 0 {
-					startExpr, o := astDeclaration.Recv.List[0].Type.(*ast.StarExpr)
-					if o {
-						ident, k := startExpr.X.(*ast.Ident)
-						if k {
-							for s, s2 := range values {
-								if s2 == ident.Name {
-									handlerFunName = pkgName + "." + s + "." + astDeclaration.Name.Name
-									break
-								}
-							}
-						}
+			signature := parseHandlerSignature(parser.packages, astFile, astDeclaration)
+			signature.Pos = parser.filePosition(fileName, astFile, astDeclaration.Pos())
+			if signature.Warn != nil {
+				parser.debug.Printf("warning: %s: %s\n", signature.Pos, signature.Warn)
+			}
+			if recv, ok := parseHandlerReceiver(astDeclaration.Recv); ok {
+				signature.Owner = recv
+				for s, s2 := range values {
+					if s2 == recv.Type {
+						handlerFunName = pkgName + "." + s + "." + astDeclaration.Name.Name
+						break
 					}
 				}
+				if handlerFunName == "" {
+					signature.Receiver = recv
+					handlerFunName = pkgName + "." + recv.String() + "." + astDeclaration.Name.Name
+				}
 			}
 			// for per 'function' comment, create a new 'Operation' object
 			operation := NewOperation(parser, SetCodeExampleFilesDirectory(parser.codeExampleFilesDir))
@@ -857,7 +963,19 @@ func (parser *Parser) ParseRouterAPIInfo(fileName string, astFile *ast.File) err
 			if handlerFunName == "" {
 				handlerFunName = pkgName + "." + astDeclaration.Name.Name
 			}
-			err := processRouterOperation(parser, operation, handlerFunName, fileName)
+			if parser.inferParams {
+				err := parser.inferOperationParams(operation, astFile, astDeclaration, handlerFunName+" at "+signature.Pos)
+				if err != nil {
+					return err
+				}
+			}
+			if parser.inferResponses {
+				err := parser.inferOperationResponses(operation, astFile, astDeclaration, handlerFunName+" at "+signature.Pos)
+				if err != nil {
+					return err
+				}
+			}
+			err := processRouterOperation(parser, operation, handlerFunName, signature, fileName)
 			if err != nil {
 				return err
 			}
@@ -867,6 +985,95 @@ func (parser *Parser) ParseRouterAPIInfo(fileName string, astFile *ast.File) err
 	return nil
 }
 
+// getTagsFromComment returns the tags of a @Tags comment.
+func getTagsFromComment(comment string) (tags []string) {
+	commentLine := strings.TrimSpace(strings.TrimLeft(comment, "/"))
+	if len(commentLine) == 0 {
+		return nil
+	}
+
+	fields := FieldsByAnySpace(commentLine, 2)
+	if strings.ToLower(fields[0]) != tagsAttr || len(fields) < 2 {
+		return nil
+	}
+
+	for _, tag := range strings.Split(fields[1], ",") {
+		tags = append(tags, strings.TrimSpace(tag))
+	}
+
+	return tags
+}
+
+// matchTags reports whether the operation of comments passes the tags filter.
+func (parser *Parser) matchTags(comments []*ast.Comment) bool {
+	var tags []string
+	for _, comment := range comments {
+		tags = append(tags, getTagsFromComment(comment.Text)...)
+	}
+
+	return parser.matchTagList(tags)
+}
+
+// matchTagList reports whether tags has no excluded tag and, unless the filter only excludes, an included tag.
+func (parser *Parser) matchTagList(tags []string) bool {
+	if len(parser.tags) == 0 {
+		return true
+	}
+
+	match := false
+	for _, tag := range tags {
+		if _, has := parser.tags["!"+tag]; has {
+			return false
+		}
+
+		if _, has := parser.tags[tag]; has {
+			match = true
+		}
+	}
+
+	if match {
+		return true
+	}
+
+	for tag := range parser.tags {
+		if !strings.HasPrefix(tag, "!") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchExtension reports whether comments declare the extension @x-extensionToMatch, always true without one.
+func matchExtension(extensionToMatch string, comments []*ast.Comment) bool {
+	if len(extensionToMatch) == 0 {
+		return true
+	}
+
+	for _, comment := range comments {
+		commentLine := strings.TrimSpace(strings.TrimLeft(comment.Text, "/"))
+
+		fields := FieldsByAnySpace(commentLine, 2)
+		if len(fields) > 0 && strings.ToLower(fields[0]) == "@x-"+strings.ToLower(extensionToMatch) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filePosition returns the file:line of pos in astFile, or fileName when astFile was not collected.
+func (parser *Parser) filePosition(fileName string, astFile *ast.File, pos token.Pos) string {
+	info, ok := parser.packages.files[astFile]
+	if !ok || info.FileSet == nil {
+		return fileName
+	}
+
+	position := info.FileSet.Position(pos)
+
+	return fmt.Sprintf("%s:%d", position.Filename, position.Line)
+}
+
 func refRouteMethodOp(item *spec.PathItem, method string) (op **spec.Operation) {
 	switch method {
 	case http.MethodGet:
@@ -888,7 +1095,18 @@ func refRouteMethodOp(item *spec.PathItem, method string) (op **spec.Operation)
 	return
 }
 
-func processRouterOperation(parser *Parser, operation *Operation, funcName string, fileName string) error {
+// setDeprecatedRouterDir fills FilePathHandlerFunc and PkgName for path like the parent layout, when handlerDir has a parent.
+func (parser *Parser) setDeprecatedRouterDir(path, handlerDir string) {
+	routerDir, err := upLayout(1).RouterDir(handlerDir)
+	if err != nil {
+		return
+	}
+
+	parser.FilePathHandlerFunc[path] = routerDir + string(filepath.Separator)
+	parser.PkgName[path] = strings.Replace(filepath.Base(routerDir), "-", "_", -1)
+}
+
+func processRouterOperation(parser *Parser, operation *Operation, funcName string, signature HandlerSignature, fileName string) error {
 	for _, routeProperties := range operation.RouterProperties {
 		var (
 			pathItem spec.PathItem
@@ -896,13 +1114,20 @@ func processRouterOperation(parser *Parser, operation *Operation, funcName strin
 		)
 
 		if funcName != "" {
-			parser.HandlerFunc[routeProperties.Path] = funcName
-			api, _ := filepath.Split(filepath.Clean(fileName))
-			service, _ := filepath.Split(filepath.Clean(api))
-			temp := strings.Split(fileName, string(filepath.Separator))
-			parser.FilePathHandlerFunc[routeProperties.Path] = service
-			parser.PkgName[routeProperties.Path] = strings.Replace(temp[len(temp)-3], "-", "_", -1)
-			parser.HandlerFuncModules[routeProperties.Path] = api
+			key := RouteKey{Method: routeProperties.HTTPMethod, Path: routeProperties.Path}
+			parser.HandlerFunc[key] = funcName
+			parser.HandlerFuncModules[key] = filepath.Dir(fileName)
+			parser.setDeprecatedRouterDir(routeProperties.Path, filepath.Dir(fileName))
+			parser.HandlerSignatures[key] = signature
+			parser.RoutePath[key] = routeProperties.RawPath
+			parser.Middlewares[key] = operation.Middlewares
+			parser.BodyTypes[key] = operation.BodyType
+			parser.RouteDeclarations = append(parser.RouteDeclarations, RouteDeclaration{
+				Method:     routeProperties.HTTPMethod,
+				RawPath:    routeProperties.RawPath,
+				HandlerFun: funcName,
+				Pos:        signature.Pos,
+			})
 		}
 
 		pathItem, ok = parser.swagger.Paths.Paths[routeProperties.Path]
@@ -593,6 +593,24 @@ func TestParser_ParseGeneralAPITagDocs(t *testing.T) {
 	assert.Equal(t, expected, string(b))
 }
 
+func TestParser_ParseGeneralAPITagMiddleware(t *testing.T) {
+	t.Parallel()
+
+	parser := New()
+	assert.Error(t, parseGeneralAPIInfo(parser, []string{
+		"@tag.middleware auth.RequireAdmin"}))
+
+	parser = New()
+	err := parseGeneralAPIInfo(parser, []string{
+		"@tag.name admin",
+		"@tag.middleware auth.RequireAdmin, audit.Log"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Middleware{
+		{Package: "auth", Name: "RequireAdmin"},
+		{Package: "audit", Name: "Log"},
+	}, parser.TagMiddlewares["admin"])
+}
+
 func TestParser_ParseGeneralAPISecurity(t *testing.T) {
 	t.Run("ApiKey", func(t *testing.T) {
 		t.Parallel()
@@ -2690,6 +2708,65 @@ func Test(){
 	assert.NotNil(t, val.Get)
 }
 
+func TestParser_ParseRouterApiMethodHandler(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package test
+
+var UserApi = new(User)
+
+type User struct{}
+
+type Controller struct{}
+
+// @Router /users [get]
+func (u *User) List(){
+}
+
+// @Router /accounts/{id} [get]
+func (c *Controller) ShowAccount(){
+}
+`
+	p := New()
+	err := p.packages.ParseFile("api", "api/api.go", src, ParseAll)
+	assert.NoError(t, err)
+
+	err = p.packages.RangeFiles(p.ParseRouterAPIInfo)
+	assert.NoError(t, err)
+
+	users := RouteKey{Method: "GET", Path: "/users"}
+	assert.Equal(t, "test.UserApi.List", p.HandlerFunc[users])
+	assert.Equal(t, HandlerReceiver{}, p.HandlerSignatures[users].Receiver)
+
+	accounts := RouteKey{Method: "GET", Path: "/accounts/{id}"}
+	assert.Equal(t, "test.(*Controller).ShowAccount", p.HandlerFunc[accounts])
+	assert.Equal(t, HandlerReceiver{Type: "Controller", Pointer: true}, p.HandlerSignatures[accounts].Receiver)
+}
+
+func TestParser_ParseRouterApiDeprecatedRouterDir(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package api
+
+// @Router /users [get]
+func ListUsers(){
+}
+`
+	p := New()
+	err := p.packages.ParseFile("user-service/api", "user-service/api/api.go", src, ParseAll)
+	assert.NoError(t, err)
+
+	err = p.packages.RangeFiles(p.ParseRouterAPIInfo)
+	assert.NoError(t, err)
+
+	routerDir, err := filepath.Abs("user-service")
+	assert.NoError(t, err)
+	assert.Equal(t, routerDir+string(filepath.Separator), p.FilePathHandlerFunc["/users"])
+	assert.Equal(t, "user_service", p.PkgName["/users"])
+}
+
 func TestParser_ParseRouterApiPOST(t *testing.T) {
 	t.Parallel()
 
@@ -3879,6 +3956,12 @@ func TestParser_matchTags(t *testing.T) {
 			args:      args{comments: []*ast.Comment{{Text: "//@Tags tag1,tag2,tag3"}}},
 			wantMatch: true,
 		},
+		{
+			name:      "with exclude only tags filter",
+			parser:    New(SetTags("!tag4")),
+			args:      args{comments: []*ast.Comment{{Text: "//@Tags tag1,tag2,tag3"}}},
+			wantMatch: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,415 @@
+package swag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RouteDeclaration is a @Router comment, kept even when a later comment replaces its operation.
+type RouteDeclaration struct {
+	// Method is the HTTP method in upper case
+	Method string
+
+	// RawPath is the path as annotated
+	RawPath string
+
+	// HandlerFun is the annotated handler, like api.GetUser
+	HandlerFun string
+
+	// Pos is the file:line of the annotated function
+	Pos string
+}
+
+// String returns the declaration as method path (handler at file:line).
+func (decl RouteDeclaration) String() string {
+	return fmt.Sprintf("%s %s (%s at %s)", decl.Method, decl.RawPath, decl.HandlerFun, decl.Pos)
+}
+
+// RouteConflict is a pair of declarations the router can not register together.
+type RouteConflict struct {
+	Existing RouteDeclaration
+	New      RouteDeclaration
+	Reason   string
+}
+
+func (conflict RouteConflict) Error() string {
+	return fmt.Sprintf("%s conflicts with %s: %s", conflict.New, conflict.Existing, conflict.Reason)
+}
+
+// RouteConflictsError lists every conflict found in the declared routes.
+type RouteConflictsError []RouteConflict
+
+func (conflicts RouteConflictsError) Error() string {
+	lines := []string{fmt.Sprintf("%d route conflicts, the router would panic when registering them:", len(conflicts))}
+	for _, conflict := range conflicts {
+		lines = append(lines, "\t"+conflict.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// CheckRouteConflicts returns a RouteConflictsError when the routes parsed by p can not all be registered on
+// backend, gin when nil, so the conflicts are reported before anything is generated.
+func CheckRouteConflicts(p *Parser, backend RouterBackend) error {
+	if backend == nil {
+		backend = ginBackend{}
+	}
+
+	// only the tree of gin is simulated, other backends are checked for duplicate routes
+	_, radix := backend.(ginBackend)
+
+	conflicts, err := findRouteConflicts(p.RouteDeclarations, backend.Path, radix)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return conflicts
+	}
+
+	return nil
+}
+
+// findRouteConflicts registers the declarations in the order of the generated routers, by path then method,
+// into a tree per method built with the insertion rules of gin when radix is set, which rejects wildcards
+// of different names at the same position or a catch-all next to other routes. Without radix only duplicate
+// method and path are conflicts. Every declaration is paired with the first declaration it conflicts with.
+// It fails when path can not translate a declaration, which the router could not register at all.
+func findRouteConflicts(decls []RouteDeclaration, path func(rawPath string) (string, error), radix bool) (RouteConflictsError, error) {
+	type route struct {
+		decl RouteDeclaration
+		path string
+	}
+
+	routes := make([]route, 0, len(decls))
+	for _, decl := range decls {
+		routePath, err := path(decl.RawPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", decl.Pos, err)
+		}
+
+		routes = append(routes, route{decl: decl, path: routePath})
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].path != routes[j].path {
+			return routes[i].path < routes[j].path
+		}
+
+		if routes[i].decl.Method != routes[j].decl.Method {
+			return routes[i].decl.Method < routes[j].decl.Method
+		}
+
+		return routes[i].decl.Pos < routes[j].decl.Pos
+	})
+
+	var (
+		conflicts  RouteConflictsError
+		registered = make(map[string][]route)
+		trees      = make(map[string]*routeNode)
+	)
+
+	for _, r := range routes {
+		method := r.decl.Method
+		if !radix {
+			for _, existing := range registered[method] {
+				if existing.path == r.path {
+					conflicts = append(conflicts, RouteConflict{Existing: existing.decl, New: r.decl,
+						Reason: "handlers are already registered for path '" + r.path + "'"})
+
+					break
+				}
+			}
+
+			registered[method] = append(registered[method], r)
+
+			continue
+		}
+
+		if trees[method] == nil {
+			trees[method] = &routeNode{fullPath: "/"}
+		}
+
+		err := trees[method].addRoute(r.path)
+		if err == nil {
+			registered[method] = append(registered[method], r)
+
+			continue
+		}
+
+		// the failed insertion may have split edges, rebuild the tree without the new route
+		trees[method] = &routeNode{fullPath: "/"}
+		for _, existing := range registered[method] {
+			_ = trees[method].addRoute(existing.path)
+		}
+
+		// find the registered route the new one conflicts with on its own
+		conflict := RouteConflict{New: r.decl, Reason: err.Error()}
+		for _, existing := range registered[method] {
+			pair := &routeNode{fullPath: "/"}
+			if pair.addRoute(existing.path) == nil && pair.addRoute(r.path) != nil {
+				conflict.Existing = existing.decl
+
+				break
+			}
+		}
+
+		conflicts = append(conflicts, conflict)
+	}
+
+	return conflicts, nil
+}
+
+// routeNodeType is the node type of the gin tree.
+type routeNodeType uint8
+
+const (
+	staticNode routeNodeType = iota
+	rootNode
+	paramNode
+	catchAllNode
+)
+
+// routeNode is a node of the radix tree of gin without the handlers, addRoute and insertChild
+// follow tree.go of gin and return its panics as errors.
+type routeNode struct {
+	path       string
+	indices    string
+	wildChild  bool
+	nType      routeNodeType
+	children   []*routeNode
+	registered bool
+	fullPath   string
+}
+
+// addChild adds a child node, keeping the wildcard child at the end.
+func (n *routeNode) addChild(child *routeNode) {
+	if n.wildChild && len(n.children) > 0 {
+		wildcardChild := n.children[len(n.children)-1]
+		n.children = append(n.children[:len(n.children)-1], child, wildcardChild)
+	} else {
+		n.children = append(n.children, child)
+	}
+}
+
+func (n *routeNode) addRoute(path string) error {
+	fullPath := path
+
+	// Empty tree
+	if len(n.path) == 0 && len(n.children) == 0 {
+		err := n.insertChild(path, fullPath)
+		if err != nil {
+			return err
+		}
+
+		n.nType = rootNode
+
+		return nil
+	}
+
+	parentFullPathIndex := 0
+
+walk:
+	for {
+		// Find the longest common prefix, which contains no ':' or '*'
+		i := 0
+		for i < len(path) && i < len(n.path) && path[i] == n.path[i] {
+			i++
+		}
+
+		// Split edge
+		if i < len(n.path) {
+			child := routeNode{
+				path:       n.path[i:],
+				wildChild:  n.wildChild,
+				nType:      staticNode,
+				indices:    n.indices,
+				children:   n.children,
+				registered: n.registered,
+				fullPath:   n.fullPath,
+			}
+
+			n.children = []*routeNode{&child}
+			n.indices = string([]byte{n.path[i]})
+			n.path = path[:i]
+			n.registered = false
+			n.wildChild = false
+			n.fullPath = fullPath[:parentFullPathIndex+i]
+		}
+
+		// Make new node a child of this node
+		if i < len(path) {
+			path = path[i:]
+			c := path[0]
+
+			// '/' after param
+			if n.nType == paramNode && c == '/' && len(n.children) == 1 {
+				parentFullPathIndex += len(n.path)
+				n = n.children[0]
+
+				continue walk
+			}
+
+			// Check if a child with the next path byte exists
+			for i := 0; i < len(n.indices); i++ {
+				if c == n.indices[i] {
+					parentFullPathIndex += len(n.path)
+					n = n.children[i]
+
+					continue walk
+				}
+			}
+
+			// Otherwise insert it
+			if c != ':' && c != '*' && n.nType != catchAllNode {
+				n.indices += string([]byte{c})
+				child := &routeNode{fullPath: fullPath}
+				n.addChild(child)
+				n = child
+			} else if n.wildChild {
+				// inserting a wildcard node, need to check if it conflicts with the existing wildcard
+				n = n.children[len(n.children)-1]
+
+				// Check if the wildcard matches
+				if len(path) >= len(n.path) && n.path == path[:len(n.path)] &&
+					// Adding a child to a catchAll is not possible
+					n.nType != catchAllNode &&
+					// Check for longer wildcard, e.g. :name and :names
+					(len(n.path) >= len(path) || path[len(n.path)] == '/') {
+					continue walk
+				}
+
+				// Wildcard conflict
+				pathSeg := path
+				if n.nType != catchAllNode {
+					pathSeg = strings.SplitN(pathSeg, "/", 2)[0]
+				}
+
+				prefix := fullPath[:strings.Index(fullPath, pathSeg)] + n.path
+
+				return fmt.Errorf("'%s' in new path '%s' conflicts with existing wildcard '%s' in existing prefix '%s'",
+					pathSeg, fullPath, n.path, prefix)
+			}
+
+			return n.insertChild(path, fullPath)
+		}
+
+		// Otherwise add handle to current node
+		if n.registered {
+			return fmt.Errorf("handlers are already registered for path '%s'", fullPath)
+		}
+
+		n.registered = true
+		n.fullPath = fullPath
+
+		return nil
+	}
+}
+
+// findWildcard returns the first wildcard segment of path, valid when its name has no ':' or '*'.
+func findWildcard(path string) (wildcard string, i int, valid bool) {
+	for start, c := range []byte(path) {
+		if c != ':' && c != '*' {
+			continue
+		}
+
+		valid = true
+		for end, c := range []byte(path[start+1:]) {
+			switch c {
+			case '/':
+				return path[start : start+1+end], start, valid
+			case ':', '*':
+				valid = false
+			}
+		}
+
+		return path[start:], start, valid
+	}
+
+	return "", -1, false
+}
+
+func (n *routeNode) insertChild(path string, fullPath string) error {
+	for {
+		wildcard, i, valid := findWildcard(path)
+		if i < 0 {
+			break
+		}
+
+		if !valid {
+			return fmt.Errorf("only one wildcard per path segment is allowed, has: '%s' in path '%s'", wildcard, fullPath)
+		}
+
+		if len(wildcard) < 2 {
+			return fmt.Errorf("wildcards must be named with a non-empty name in path '%s'", fullPath)
+		}
+
+		if wildcard[0] == ':' {
+			if i > 0 {
+				// Insert prefix before the current wildcard
+				n.path = path[:i]
+				path = path[i:]
+			}
+
+			child := &routeNode{nType: paramNode, path: wildcard, fullPath: fullPath}
+			n.addChild(child)
+			n.wildChild = true
+			n = child
+
+			// if the path doesn't end with the wildcard, then there will be another subpath starting with '/'
+			if len(wildcard) < len(path) {
+				path = path[len(wildcard):]
+
+				child := &routeNode{fullPath: fullPath}
+				n.addChild(child)
+				n = child
+
+				continue
+			}
+
+			n.registered = true
+
+			return nil
+		}
+
+		// catchAll
+		if i+len(wildcard) != len(path) {
+			return fmt.Errorf("catch-all routes are only allowed at the end of the path in path '%s'", fullPath)
+		}
+
+		if len(n.path) > 0 && n.path[len(n.path)-1] == '/' {
+			pathSeg := ""
+			if len(n.children) > 0 {
+				pathSeg = strings.SplitN(n.children[0].path, "/", 2)[0]
+			}
+
+			return fmt.Errorf("catch-all wildcard '%s' in new path '%s' conflicts with existing path segment '%s' in existing prefix '%s'",
+				path, fullPath, pathSeg, n.path+pathSeg)
+		}
+
+		i--
+		if i < 0 || path[i] != '/' {
+			return fmt.Errorf("no / before catch-all in path '%s'", fullPath)
+		}
+
+		n.path = path[:i]
+
+		// First node: catchAll node with empty path
+		child := &routeNode{wildChild: true, nType: catchAllNode, fullPath: fullPath}
+		n.addChild(child)
+		n.indices = "/"
+		n = child
+
+		// second node: node holding the variable
+		n.children = []*routeNode{{path: path[i:], nType: catchAllNode, registered: true, fullPath: fullPath}}
+
+		return nil
+	}
+
+	// If no wildcard was found, simply insert the path and handle
+	n.path = path
+	n.registered = true
+	n.fullPath = fullPath
+
+	return nil
+}
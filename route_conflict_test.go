@@ -0,0 +1,100 @@
+package swag
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRouteConflicts(t *testing.T) {
+	t.Parallel()
+
+	decls := []RouteDeclaration{
+		{Method: "GET", RawPath: "/users/{id}", HandlerFun: "api.GetUser", Pos: "api/user.go:10"},
+		{Method: "GET", RawPath: "/users/new", HandlerFun: "api.NewUser", Pos: "api/user.go:20"},
+		{Method: "GET", RawPath: "/users/{name}/posts", HandlerFun: "blog.ListPosts", Pos: "blog/post.go:8"},
+		{Method: "GET", RawPath: "/files/{path*}", HandlerFun: "api.GetFile", Pos: "api/file.go:5"},
+		{Method: "GET", RawPath: "/files/readme", HandlerFun: "api.GetReadme", Pos: "api/file.go:15"},
+		{Method: "POST", RawPath: "/users", HandlerFun: "api.CreateUser", Pos: "api/user.go:30"},
+		{Method: "POST", RawPath: "/users", HandlerFun: "admin.CreateUser", Pos: "admin/user.go:12"},
+		{Method: "PUT", RawPath: "/users/{name}", HandlerFun: "api.UpdateUser", Pos: "api/user.go:40"},
+	}
+
+	conflicts, err := findRouteConflicts(decls, GinPath, true)
+	assert.NoError(t, err)
+	assert.Equal(t, RouteConflictsError{
+		{
+			Existing: decls[3],
+			New:      decls[4],
+			Reason:   "'/readme' in new path '/files/readme' conflicts with existing wildcard '/*path' in existing prefix '/files/*path'",
+		},
+		{
+			Existing: decls[6],
+			New:      decls[5],
+			Reason:   "handlers are already registered for path '/users'",
+		},
+		{
+			Existing: decls[0],
+			New:      decls[2],
+			Reason:   "':name' in new path '/users/:name/posts' conflicts with existing wildcard ':id' in existing prefix '/users/:id'",
+		},
+	}, conflicts)
+	assert.Contains(t, conflicts.Error(), "GET /users/{name}/posts (blog.ListPosts at blog/post.go:8) conflicts with "+
+		"GET /users/{id} (api.GetUser at api/user.go:10)")
+
+	conflicts, err = findRouteConflicts(decls, GinPath, false)
+	assert.NoError(t, err)
+	assert.Equal(t, RouteConflictsError{{Existing: decls[6], New: decls[5], Reason: "handlers are already registered for path '/users'"}}, conflicts)
+
+	_, err = findRouteConflicts([]RouteDeclaration{{Method: "GET", RawPath: "/users/{id}.json", Pos: "api/user.go:1"}}, GinPath, true)
+	assert.EqualError(t, err, `api/user.go:1: route /users/{id}.json: gin only supports path parameters spanning a whole segment, got "{id}.json"`)
+}
+
+func TestRouteTreeMatchesGin(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	tests := [][]string{
+		{"/users/:id", "/users/new"},
+		{"/users/new", "/users/:id"},
+		{"/users/:id", "/users/:name"},
+		{"/users/:id", "/users/:id/posts"},
+		{"/users/:id/posts", "/users/:name/likes"},
+		{"/users/:id", "/users/:idx"},
+		{"/files/*path", "/files/readme"},
+		{"/files/readme", "/files/*path"},
+		{"/files/*path", "/files/:name"},
+		{"/files/", "/files/*path"},
+		{"/files/*path", "/files/*name"},
+		{"/", "/*path"},
+		{"/users", "/users/"},
+		{"/users", "/users"},
+		{"/src/*path", "/src"},
+		{"/a/:b/c", "/a/:b/d", "/a/x/c"},
+	}
+	for _, paths := range tests {
+		tree := &routeNode{fullPath: "/"}
+
+		var treeErr error
+		for _, path := range paths {
+			if treeErr = tree.addRoute(path); treeErr != nil {
+				break
+			}
+		}
+
+		panicked := func() (panicked bool) {
+			defer func() {
+				panicked = recover() != nil
+			}()
+
+			engine := gin.New()
+			for _, path := range paths {
+				engine.GET(path, func(*gin.Context) {})
+			}
+
+			return false
+		}()
+
+		assert.Equal(t, panicked, treeErr != nil, "%v: %v", paths, treeErr)
+	}
+}
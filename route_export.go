@@ -0,0 +1,116 @@
+package swag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+)
+
+// Route table output types, generated next to resource.go from the same RouteInfos.
+const (
+	RoutesJSON = "routes.json"
+	RoutesYAML = "routes.yaml"
+	RoutesYML  = "routes.yml"
+	RoutesSQL  = "routes.sql"
+)
+
+// IsRouteOutputType reports whether outputType is an export of the route table.
+func IsRouteOutputType(outputType string) bool {
+	switch outputType {
+	case RoutesJSON, RoutesYAML, RoutesYML, RoutesSQL:
+		return true
+	}
+
+	return false
+}
+
+// DefaultRoutesSQLTemplate upserts every route into the table api_routes, which needs a unique key on
+// (method, path), so the file can be applied again after the routes changed. The ON CONFLICT clause is
+// understood by PostgreSQL and SQLite, use --routesSQLTemplate for another database.
+const DefaultRoutesSQLTemplate = `-- Code generated by swag-gin. DO NOT EDIT.
+{{range .}}
+INSERT INTO api_routes (method, path, base_path, handler_fun, summary, group_name, public, deprecated, operation_id, tags)
+VALUES ({{quote .Method}}, {{quote .Path}}, {{quote .BasePath}}, {{quote .HandlerFun}}, {{quote .Summary}}, {{quote .GroupName}}, {{.Public}}, {{.Deprecated}}, {{quote .OperationID}}, {{quote (join .Tags ",")}})
+ON CONFLICT (method, path) DO UPDATE SET base_path = EXCLUDED.base_path, handler_fun = EXCLUDED.handler_fun, summary = EXCLUDED.summary, group_name = EXCLUDED.group_name, public = EXCLUDED.public, deprecated = EXCLUDED.deprecated, operation_id = EXCLUDED.operation_id, tags = EXCLUDED.tags;
+{{end}}`
+
+// routesSQLFuncs are the functions available to a routes.sql template.
+var routesSQLFuncs = template.FuncMap{
+	// quote returns s as a SQL string literal
+	"quote": func(s string) string {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	},
+	"join": strings.Join,
+	// json returns v encoded as JSON, for json columns
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+
+		return string(data), err
+	},
+}
+
+// genRouteExports writes the route table in each route output type of config. Like the routers, the exports
+// are always rewritten, a stale route table is never kept.
+func genRouteExports(infos []RouteInfos, config GenConfig) error {
+	for _, outputType := range config.RouteOutputs {
+		content, err := exportRoutes(infos, outputType, config.RoutesSQLTemplate)
+		if err != nil {
+			return err
+		}
+
+		err = config.Writer.WriteFile(filepath.Join(config.OutputDir, outputType), content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportRoutes encodes infos in outputType, rendering sqlTemplate or else DefaultRoutesSQLTemplate for routes.sql.
+func exportRoutes(infos []RouteInfos, outputType, sqlTemplate string) ([]byte, error) {
+	if infos == nil {
+		infos = []RouteInfos{}
+	}
+
+	switch outputType {
+	case RoutesJSON:
+		data, err := json.MarshalIndent(infos, "", "    ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(data, '\n'), nil
+	case RoutesYAML, RoutesYML:
+		data, err := json.Marshal(infos)
+		if err != nil {
+			return nil, err
+		}
+
+		return yaml.JSONToYAML(data)
+	case RoutesSQL:
+		if sqlTemplate == "" {
+			sqlTemplate = DefaultRoutesSQLTemplate
+		}
+
+		tmpl, err := template.New(RoutesSQL).Funcs(routesSQLFuncs).Parse(sqlTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("can not parse the routes.sql template: %s", err)
+		}
+
+		buf := &bytes.Buffer{}
+		err = tmpl.Execute(buf, infos)
+		if err != nil {
+			return nil, fmt.Errorf("can not render routes.sql: %s", err)
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("output type '%s' is not a route table", outputType)
+}
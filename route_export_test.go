@@ -0,0 +1,105 @@
+package swag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var exportedRoutes = []RouteInfos{
+	{
+		Method:      "get",
+		Path:        "/users/{id}",
+		GinPath:     "/users/:id",
+		BasePath:    "/api/v1",
+		HandlerFun:  "api.GetUser",
+		Summary:     "Get the user's profile",
+		RouteGroup:  RouteGroup{GroupName: "users"},
+		OperationID: "getUser",
+		Tags:        []string{"users", "profile"},
+		Responses:   []int{200, 404},
+	},
+	{
+		Method:     "post",
+		Path:       "/login",
+		GinPath:    "/login",
+		BasePath:   "/api/v1",
+		HandlerFun: "api.Login",
+		Public:     true,
+		RouteGroup: RouteGroup{GroupName: "auth"},
+	},
+}
+
+func TestExportRoutes(t *testing.T) {
+	t.Parallel()
+
+	data, err := exportRoutes(exportedRoutes[1:], RoutesJSON, "")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"method":"post","path":"/login","gin_path":"/login","base_path":"/api/v1","handler_fun":"api.Login",
+		"summary":"","public":true,"name":"auth","operation_id":"","tags":null,"security":null,"deprecated":false,
+		"description":"","consumes":null,"produces":null,"params":null,"responses":null}]`, string(data))
+
+	data, err = exportRoutes(exportedRoutes[:1], RoutesYAML, "")
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "- base_path: /api/v1\n")
+	assert.Contains(t, string(data), "  handler_fun: api.GetUser\n")
+	assert.Contains(t, string(data), "  tags:\n  - users\n  - profile\n")
+
+	data, err = exportRoutes(nil, RoutesYML, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "[]\n", string(data))
+
+	data, err = exportRoutes(exportedRoutes, RoutesSQL, "")
+	assert.NoError(t, err)
+	assert.Equal(t, `-- Code generated by swag-gin. DO NOT EDIT.
+
+INSERT INTO api_routes (method, path, base_path, handler_fun, summary, group_name, public, deprecated, operation_id, tags)
+VALUES ('get', '/users/{id}', '/api/v1', 'api.GetUser', 'Get the user''s profile', 'users', false, false, 'getUser', 'users,profile')
+ON CONFLICT (method, path) DO UPDATE SET base_path = EXCLUDED.base_path, handler_fun = EXCLUDED.handler_fun, summary = EXCLUDED.summary, group_name = EXCLUDED.group_name, public = EXCLUDED.public, deprecated = EXCLUDED.deprecated, operation_id = EXCLUDED.operation_id, tags = EXCLUDED.tags;
+
+INSERT INTO api_routes (method, path, base_path, handler_fun, summary, group_name, public, deprecated, operation_id, tags)
+VALUES ('post', '/login', '/api/v1', 'api.Login', '', 'auth', true, false, '', '')
+ON CONFLICT (method, path) DO UPDATE SET base_path = EXCLUDED.base_path, handler_fun = EXCLUDED.handler_fun, summary = EXCLUDED.summary, group_name = EXCLUDED.group_name, public = EXCLUDED.public, deprecated = EXCLUDED.deprecated, operation_id = EXCLUDED.operation_id, tags = EXCLUDED.tags;
+`, string(data))
+
+	upsert := `{{range .}}INSERT INTO permissions (route, tags) VALUES ({{quote (print .Method " " .Path)}}, {{quote (json .Tags)}})` +
+		` ON CONFLICT (route) DO UPDATE SET tags = EXCLUDED.tags;
+{{end}}`
+	data, err = exportRoutes(exportedRoutes, RoutesSQL, upsert)
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO permissions (route, tags) VALUES ('get /users/{id}', '["users","profile"]') ON CONFLICT (route) DO UPDATE SET tags = EXCLUDED.tags;
+INSERT INTO permissions (route, tags) VALUES ('post /login', 'null') ON CONFLICT (route) DO UPDATE SET tags = EXCLUDED.tags;
+`, string(data))
+
+	_, err = exportRoutes(exportedRoutes, RoutesSQL, "{{range .}")
+	assert.Error(t, err)
+
+	_, err = exportRoutes(exportedRoutes, RoutesSQL, "{{.Unknown}}")
+	assert.Error(t, err)
+
+	_, err = exportRoutes(exportedRoutes, "go", "")
+	assert.Error(t, err)
+}
+
+func TestGenRouteExports(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	config := GenConfig{OutputDir: dir, RouteOutputs: []string{RoutesJSON, RoutesSQL}}
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, RoutesSQL), []byte("-- stale\n"), 0666))
+	assert.NoError(t, genRouteExports(exportedRoutes, config))
+
+	assert.FileExists(t, filepath.Join(dir, RoutesJSON))
+	assert.NoFileExists(t, filepath.Join(dir, RoutesYAML))
+
+	// a stale export is replaced without AutoCover
+	data, err := os.ReadFile(filepath.Join(dir, RoutesSQL))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "INSERT INTO api_routes")
+
+	assert.True(t, IsRouteOutputType(RoutesYML))
+	assert.False(t, IsRouteOutputType("yaml"))
+}
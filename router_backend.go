@@ -0,0 +1,286 @@
+package swag
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// RouterBackend renders the generated routers for one web framework.
+type RouterBackend interface {
+	// Package returns the import path and name of the framework package.
+	Package() (string, string)
+
+	// RouterParam declares the parameter r the generated router functions register routes on.
+	RouterParam() jen.Code
+
+	// Path translates an annotated router path into the route syntax of the framework.
+	Path(rawPath string) (string, error)
+
+	// Handler adapts handler of kind to the framework, or returns an error when it can not be registered.
+	Handler(kind HandlerKind, handler jen.Code) (jen.Code, error)
+
+	// Route registers handler behind middlewares on r, or on the group g of a tag when grouped.
+	Route(method, path string, grouped bool, middlewares []jen.Code, handler jen.Code) jen.Code
+
+	// Group declares the group g of tag, applying middlewares to the routes registered on it.
+	Group(tag string, middlewares []jen.Code, routes []jen.Code) jen.Code
+}
+
+// ParseRouterBackend returns the backend of a web framework: gin (the default), echo, chi
+// or servemux for the method patterns of net/http.ServeMux since Go 1.22.
+func ParseRouterBackend(name string) (RouterBackend, error) {
+	switch name {
+	case "", "gin":
+		return ginBackend{}, nil
+	case "echo":
+		return echoBackend{}, nil
+	case "chi":
+		return chiBackend{}, nil
+	case "servemux":
+		return serveMuxBackend{}, nil
+	}
+
+	return nil, fmt.Errorf("invalid router backend %s, expected one of gin, echo, chi, servemux", name)
+}
+
+// unsupportedHandler reports a handler of kind the backend named framework can not register.
+func unsupportedHandler(kind HandlerKind, framework string) error {
+	return fmt.Errorf("%s handler can not be registered with the %s router backend", kind, framework)
+}
+
+func groupReceiver(grouped bool) string {
+	if grouped {
+		return "g"
+	}
+
+	return "r"
+}
+
+// ginBackend registers routes on a *gin.RouterGroup.
+type ginBackend struct{}
+
+func (ginBackend) Package() (string, string) {
+	return ginImportPath, "gin"
+}
+
+func (b ginBackend) RouterParam() jen.Code {
+	pkg, _ := b.Package()
+
+	return jen.Id("r").Op("*").Qual(pkg, "RouterGroup")
+}
+
+func (ginBackend) Path(rawPath string) (string, error) {
+	return GinPath(rawPath)
+}
+
+// Handler wraps net/http handlers with gin.WrapF or gin.WrapH, handler is the generated adapter of a typed handler.
+func (ginBackend) Handler(kind HandlerKind, handler jen.Code) (jen.Code, error) {
+	switch kind {
+	case "", HandlerKindGin, HandlerKindTyped:
+		return handler, nil
+	case HandlerKindHTTPFunc:
+		return jen.Qual(ginImportPath, "WrapF").Call(handler), nil
+	case HandlerKindHTTP:
+		return jen.Qual(ginImportPath, "WrapH").Call(handler), nil
+	}
+
+	return nil, unsupportedHandler(kind, "gin")
+}
+
+func (ginBackend) Route(method, path string, grouped bool, middlewares []jen.Code, handler jen.Code) jen.Code {
+	args := append([]jen.Code{jen.Lit(path)}, middlewares...)
+
+	return jen.Id(groupReceiver(grouped)).Dot(strings.ToUpper(method)).Call(append(args, handler)...)
+}
+
+func (ginBackend) Group(tag string, middlewares []jen.Code, routes []jen.Code) jen.Code {
+	return jen.Block(append([]jen.Code{
+		jen.Comment(tag),
+		jen.Id("g").Op(":=").Id("r").Dot("Group").Call(append([]jen.Code{jen.Lit("")}, middlewares...)...),
+	}, routes...)...)
+}
+
+// echoBackend registers routes on an *echo.Group, a catch-all parameter is read with c.Param("*").
+type echoBackend struct{}
+
+func (echoBackend) Package() (string, string) {
+	return echoImportPath, "echo"
+}
+
+func (b echoBackend) RouterParam() jen.Code {
+	pkg, _ := b.Package()
+
+	return jen.Id("r").Op("*").Qual(pkg, "Group")
+}
+
+func (echoBackend) Path(rawPath string) (string, error) {
+	return translatePath(rawPath, "echo", func(name string) string {
+		return ":" + name
+	}, func(string) string {
+		return "*"
+	})
+}
+
+// Handler wraps net/http handlers with echo.WrapHandler.
+func (echoBackend) Handler(kind HandlerKind, handler jen.Code) (jen.Code, error) {
+	switch kind {
+	case "", HandlerKindEcho:
+		return handler, nil
+	case HandlerKindHTTPFunc:
+		return jen.Qual(echoImportPath, "WrapHandler").Call(jen.Qual(httpImportPath, "HandlerFunc").Call(handler)), nil
+	case HandlerKindHTTP:
+		return jen.Qual(echoImportPath, "WrapHandler").Call(handler), nil
+	}
+
+	return nil, unsupportedHandler(kind, "echo")
+}
+
+func (echoBackend) Route(method, path string, grouped bool, middlewares []jen.Code, handler jen.Code) jen.Code {
+	args := append([]jen.Code{jen.Lit(path), handler}, middlewares...)
+
+	return jen.Id(groupReceiver(grouped)).Dot(strings.ToUpper(method)).Call(args...)
+}
+
+func (echoBackend) Group(tag string, middlewares []jen.Code, routes []jen.Code) jen.Code {
+	return ginBackend{}.Group(tag, middlewares, routes)
+}
+
+// chiBackend registers routes on a chi.Router, a catch-all parameter is read with chi.URLParam(r, "*").
+type chiBackend struct{}
+
+func (chiBackend) Package() (string, string) {
+	return "github.com/go-chi/chi/v5", "chi"
+}
+
+func (b chiBackend) RouterParam() jen.Code {
+	pkg, _ := b.Package()
+
+	return jen.Id("r").Qual(pkg, "Router")
+}
+
+func (chiBackend) Path(rawPath string) (string, error) {
+	return translatePath(rawPath, "chi", func(name string) string {
+		return "{" + name + "}"
+	}, func(string) string {
+		return "*"
+	})
+}
+
+func (chiBackend) Handler(kind HandlerKind, handler jen.Code) (jen.Code, error) {
+	return httpHandler(kind, handler, "chi")
+}
+
+func (chiBackend) Route(method, path string, grouped bool, middlewares []jen.Code, handler jen.Code) jen.Code {
+	router := jen.Id(groupReceiver(grouped))
+	if len(middlewares) > 0 {
+		router = router.Dot("With").Call(middlewares...)
+	}
+
+	return router.Dot(strings.ToUpper(method[:1])+strings.ToLower(method[1:])).Call(jen.Lit(path), handler)
+}
+
+func (b chiBackend) Group(tag string, middlewares []jen.Code, routes []jen.Code) jen.Code {
+	pkg, _ := b.Package()
+	body := []jen.Code{jen.Comment(tag)}
+	if len(middlewares) > 0 {
+		body = append(body, jen.Id("g").Dot("Use").Call(middlewares...))
+	}
+
+	return jen.Id("r").Dot("Group").Call(jen.Func().Params(jen.Id("g").Qual(pkg, "Router")).Block(append(body, routes...)...))
+}
+
+// basePathBackend is a RouterBackend without route groups, whose paths carry the @BasePath of the API instead.
+type basePathBackend interface {
+	// WithBasePath returns the backend prefixing every route path with basePath.
+	WithBasePath(basePath string) RouterBackend
+}
+
+// serveMuxBackend registers method patterns on a *http.ServeMux, middlewares are func(http.Handler) http.Handler.
+// ServeMux has no groups, so the patterns are prefixed with the @BasePath.
+type serveMuxBackend struct {
+	basePath string
+}
+
+func (b serveMuxBackend) WithBasePath(basePath string) RouterBackend {
+	b.basePath = strings.TrimSuffix(basePath, "/")
+
+	return b
+}
+
+func (serveMuxBackend) Package() (string, string) {
+	return httpImportPath, "http"
+}
+
+func (b serveMuxBackend) RouterParam() jen.Code {
+	pkg, _ := b.Package()
+
+	return jen.Id("r").Op("*").Qual(pkg, "ServeMux")
+}
+
+func (b serveMuxBackend) Path(rawPath string) (string, error) {
+	routePath, err := translatePath(rawPath, "servemux", func(name string) string {
+		return "{" + name + "}"
+	}, func(name string) string {
+		return "{" + name + "...}"
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return b.basePath + routePath, nil
+}
+
+func (serveMuxBackend) Handler(kind HandlerKind, handler jen.Code) (jen.Code, error) {
+	return httpHandler(kind, handler, "servemux")
+}
+
+func (b serveMuxBackend) Route(method, path string, grouped bool, middlewares []jen.Code, handler jen.Code) jen.Code {
+	pattern := jen.Lit(strings.ToUpper(method) + " " + path)
+	if len(middlewares) == 0 && !grouped {
+		return jen.Id("r").Dot("HandleFunc").Call(pattern, handler)
+	}
+
+	pkg, _ := b.Package()
+	wrapped := b.wrap(middlewares, jen.Qual(pkg, "HandlerFunc").Call(handler))
+	if grouped {
+		return jen.Id("g").Call(pattern, wrapped)
+	}
+
+	return jen.Id("r").Dot("Handle").Call(pattern, wrapped)
+}
+
+// Group declares g as a function registering a pattern behind the tag middlewares, ServeMux has no groups.
+func (b serveMuxBackend) Group(tag string, middlewares []jen.Code, routes []jen.Code) jen.Code {
+	pkg, _ := b.Package()
+	register := jen.Func().Params(jen.Id("pattern").String(), jen.Id("handler").Qual(pkg, "Handler")).Block(
+		jen.Id("r").Dot("Handle").Call(jen.Id("pattern"), b.wrap(middlewares, jen.Id("handler"))),
+	)
+
+	return jen.Block(append([]jen.Code{
+		jen.Comment(tag),
+		jen.Id("g").Op(":=").Add(register),
+	}, routes...)...)
+}
+
+// wrap applies middlewares around handler, the first middleware running first.
+func (serveMuxBackend) wrap(middlewares []jen.Code, handler jen.Code) jen.Code {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = jen.Add(middlewares[i]).Call(handler)
+	}
+
+	return handler
+}
+
+// httpHandler registers net/http handlers for the backends built on net/http, an http.Handler by its ServeHTTP method.
+func httpHandler(kind HandlerKind, handler jen.Code, framework string) (jen.Code, error) {
+	switch kind {
+	case "", HandlerKindHTTPFunc:
+		return handler, nil
+	case HandlerKindHTTP:
+		return jen.Add(handler).Dot("ServeHTTP"), nil
+	}
+
+	return nil, unsupportedHandler(kind, framework)
+}
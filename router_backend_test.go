@@ -0,0 +1,183 @@
+package swag
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRouterBackend(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"", "gin", "echo", "chi", "servemux"} {
+		_, err := ParseRouterBackend(name)
+		assert.NoError(t, err, name)
+	}
+
+	_, err := ParseRouterBackend("fiber")
+	assert.Error(t, err)
+}
+
+func TestRouterBackendPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		backend  string
+		expected string
+	}{
+		{"gin", "/users/:id/files/*path"},
+		{"echo", "/users/:id/files/*"},
+		{"chi", "/users/{id}/files/*"},
+		{"servemux", "/users/{id}/files/{path...}"},
+	}
+	for _, test := range tests {
+		backend, err := ParseRouterBackend(test.backend)
+		assert.NoError(t, err)
+
+		routePath, err := backend.Path("/users/{id}/files/{path*}")
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, routePath, test.backend)
+
+		_, err = backend.Path("/users/{id}.json")
+		assert.Error(t, err, test.backend)
+	}
+}
+
+func TestServeMuxBasePath(t *testing.T) {
+	t.Parallel()
+
+	backend, err := ParseRouterBackend("servemux")
+	assert.NoError(t, err)
+
+	for basePath, expected := range map[string]string{
+		"":        "/users/{id}",
+		"/":       "/users/{id}",
+		"/api/v1": "/api/v1/users/{id}",
+		"/api/":   "/api/users/{id}",
+	} {
+		routePath, err := backend.(basePathBackend).WithBasePath(basePath).Path("/users/{id}")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, routePath, basePath)
+	}
+}
+
+func TestRouterBackendRoute(t *testing.T) {
+	t.Parallel()
+
+	handler := jen.Qual("example.com/api", "GetUser")
+	middlewares := []jen.Code{jen.Qual("example.com/auth", "Admin"), jen.Qual("example.com/audit", "Log")}
+
+	tests := []struct {
+		backend  string
+		plain    string
+		withMW   string
+		grouped  string
+		groupSrc string
+	}{
+		{
+			backend:  "gin",
+			plain:    `r.GET("/users/:id", api.GetUser)`,
+			withMW:   `r.GET("/users/:id", auth.Admin, audit.Log, api.GetUser)`,
+			grouped:  `g.GET("/users/:id", api.GetUser)`,
+			groupSrc: "{\n\t\t// users\n\t\tg := r.Group(\"\", auth.Admin, audit.Log)\n\t\tg.GET(\"/users/:id\", api.GetUser)\n\t}",
+		},
+		{
+			backend:  "echo",
+			plain:    `r.GET("/users/:id", api.GetUser)`,
+			withMW:   `r.GET("/users/:id", api.GetUser, auth.Admin, audit.Log)`,
+			grouped:  `g.GET("/users/:id", api.GetUser)`,
+			groupSrc: "{\n\t\t// users\n\t\tg := r.Group(\"\", auth.Admin, audit.Log)\n\t\tg.GET(\"/users/:id\", api.GetUser)\n\t}",
+		},
+		{
+			backend:  "chi",
+			plain:    `r.Get("/users/{id}", api.GetUser)`,
+			withMW:   `r.With(auth.Admin, audit.Log).Get("/users/{id}", api.GetUser)`,
+			grouped:  `g.Get("/users/{id}", api.GetUser)`,
+			groupSrc: "r.Group(func(g chi.Router) {\n\t\t// users\n\t\tg.Use(auth.Admin, audit.Log)\n\t\tg.Get(\"/users/{id}\", api.GetUser)\n\t})",
+		},
+		{
+			backend:  "servemux",
+			plain:    `r.HandleFunc("GET /users/{id}", api.GetUser)`,
+			withMW:   `r.Handle("GET /users/{id}", auth.Admin(audit.Log(http.HandlerFunc(api.GetUser))))`,
+			grouped:  `g("GET /users/{id}", http.HandlerFunc(api.GetUser))`,
+			groupSrc: "{\n\t\t// users\n\t\tg := func(pattern string, handler http.Handler) {\n\t\t\tr.Handle(pattern, auth.Admin(audit.Log(handler)))\n\t\t}\n\t\tg(\"GET /users/{id}\", http.HandlerFunc(api.GetUser))\n\t}",
+		},
+	}
+	for _, test := range tests {
+		backend, err := ParseRouterBackend(test.backend)
+		assert.NoError(t, err)
+
+		routePath, err := backend.Path("/users/{id}")
+		assert.NoError(t, err)
+
+		assert.Equal(t, test.plain, fmt.Sprintf("%#v", backend.Route("get", routePath, false, nil, handler)), test.backend)
+		assert.Equal(t, test.withMW, fmt.Sprintf("%#v", backend.Route("get", routePath, false, middlewares, handler)), test.backend)
+
+		grouped := backend.Route("get", routePath, true, nil, handler)
+		assert.Equal(t, test.grouped, fmt.Sprintf("%#v", grouped), test.backend)
+
+		f := jen.NewFile("router")
+		f.ImportName(backend.Package())
+		f.Var().Id("_").Op("=").Func().Params(backend.RouterParam()).Block(backend.Group("users", middlewares, []jen.Code{grouped}))
+		var buf bytes.Buffer
+		assert.NoError(t, f.Render(&buf))
+		assert.Contains(t, buf.String(), test.groupSrc, test.backend)
+	}
+}
+
+func TestRouterBackendHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := jen.Qual("example.com/api", "Handle")
+
+	tests := []struct {
+		backend  string
+		expected map[HandlerKind]string
+	}{
+		{"gin", map[HandlerKind]string{
+			HandlerKindGin:      "api.Handle",
+			HandlerKindHTTPFunc: "gin.WrapF(api.Handle)",
+			HandlerKindHTTP:     "gin.WrapH(api.Handle)",
+		}},
+		{"echo", map[HandlerKind]string{
+			HandlerKindEcho:     "api.Handle",
+			HandlerKindHTTPFunc: "echo.WrapHandler(http.HandlerFunc(api.Handle))",
+			HandlerKindHTTP:     "echo.WrapHandler(api.Handle)",
+		}},
+		{"chi", map[HandlerKind]string{
+			HandlerKindHTTPFunc: "api.Handle",
+			HandlerKindHTTP:     "api.Handle.ServeHTTP",
+		}},
+		{"servemux", map[HandlerKind]string{
+			HandlerKindHTTPFunc: "api.Handle",
+			HandlerKindHTTP:     "api.Handle.ServeHTTP",
+		}},
+	}
+	for _, test := range tests {
+		backend, err := ParseRouterBackend(test.backend)
+		assert.NoError(t, err)
+
+		for _, kind := range []HandlerKind{HandlerKindGin, HandlerKindEcho, HandlerKindHTTPFunc, HandlerKindHTTP} {
+			code, err := backend.Handler(kind, handler)
+			expected, ok := test.expected[kind]
+			if !ok {
+				assert.Error(t, err, "%s %s", test.backend, kind)
+
+				continue
+			}
+
+			assert.NoError(t, err)
+
+			f := jen.NewFile("router")
+			f.ImportName(ginImportPath, "gin")
+			f.ImportName(echoImportPath, "echo")
+			f.Var().Id("_").Op("=").Add(code)
+			var buf bytes.Buffer
+			assert.NoError(t, f.Render(&buf))
+			assert.Contains(t, buf.String(), "var _ = "+expected, "%s %s", test.backend, kind)
+		}
+	}
+}
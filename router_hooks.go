@@ -0,0 +1,102 @@
+package swag
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+const (
+	// routerGenFile holds the generated routes and is rewritten by every generation.
+	routerGenFile = "router_gen.go"
+
+	// routerHookFile holds hand-written routes and is only created when missing.
+	routerHookFile = "router.go"
+
+	publicRoutesHook  = "extraPublicRoutes"
+	privateRoutesHook = "extraPrivateRoutes"
+)
+
+// genHookFile makes sure the router directory of key declares the extension hooks called by router_gen.go.
+// A router.go without both hooks is only replaced when it was generated by swag-gin and AutoCover is on,
+// otherwise it fails naming the missing hooks, as router_gen.go would not compile next to it.
+func genHookFile(key Routes, config GenConfig) error {
+	hookPath := filepath.Join(key.FilePath, routerHookFile)
+	exists, err := pathExists(hookPath)
+	if err != nil {
+		return err
+	}
+	if exists {
+		missing, generated, err := inspectRouterFile(hookPath)
+		if err != nil || len(missing) == 0 {
+			return err
+		}
+		if !generated {
+			return fmt.Errorf("%s is not generated by swag-gin and does not declare %s, which %s calls",
+				hookPath, strings.Join(missing, " and "), routerGenFile)
+		}
+		if !config.AutoCover {
+			return fmt.Errorf("%s was generated by an older version without %s, which %s calls, regenerate with --aco to replace it",
+				hookPath, strings.Join(missing, " and "), routerGenFile)
+		}
+	}
+
+	f := jen.NewFilePathName(key.ImportPath, key.PkgName)
+	f.ImportName(config.Backend.Package())
+	rParams := config.Backend.RouterParam()
+	f.Comment("This file is created once by swag-gin and never overwritten, the generated routes live in " + routerGenFile + ".")
+	f.Line()
+	f.Comment(publicRoutesHook + " registers hand-written routes at the end of InitPublicRouter.")
+	f.Func().Id(publicRoutesHook).Params(rParams).Block()
+	f.Line()
+	f.Comment(privateRoutesHook + " registers hand-written routes at the end of InitPrivateRouter.")
+	f.Func().Id(privateRoutesHook).Params(rParams).Block()
+	return renderGenFile(f, hookPath, config)
+}
+
+// generatedMarkerPattern matches the comment marking a generated Go file, see https://go.dev/s/generatedcode.
+var generatedMarkerPattern = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// inspectRouterFile returns the router extension hooks the Go file at path does not declare, and whether
+// it was generated: it carries the generated code marker, or it is the router.go of older versions of
+// swag-gin, which declared only the InitPublicRouter and InitPrivateRouter functions.
+func inspectRouterFile(path string) (missing []string, generated bool, err error) {
+	astFile, err := goparser.ParseFile(token.NewFileSet(), path, nil, goparser.ParseComments|goparser.SkipObjectResolution)
+	if err != nil {
+		return nil, false, err
+	}
+	funcs := make(map[string]bool)
+	legacy := true
+	for _, decl := range astFile.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				funcs[decl.Name.Name] = true
+			}
+			legacy = legacy && decl.Recv == nil && (decl.Name.Name == publicRouterFunc || decl.Name.Name == "InitPrivateRouter")
+		case *ast.GenDecl:
+			legacy = legacy && decl.Tok == token.IMPORT
+		}
+	}
+	for _, hook := range []string{publicRoutesHook, privateRoutesHook} {
+		if !funcs[hook] {
+			missing = append(missing, hook)
+		}
+	}
+	generated = legacy && funcs[publicRouterFunc] && funcs["InitPrivateRouter"]
+	for _, group := range astFile.Comments {
+		if group.Pos() >= astFile.Package {
+			break
+		}
+		for _, comment := range group.List {
+			generated = generated || generatedMarkerPattern.MatchString(comment.Text)
+		}
+	}
+	return missing, generated, nil
+}
@@ -0,0 +1,90 @@
+package swag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectRouterFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"hooked.go": `package router
+
+func extraPublicRoutes(r *gin.RouterGroup) {
+	r.GET("/health", health)
+}
+
+func extraPrivateRoutes(r *gin.RouterGroup) {}
+`,
+		"public.go": `package router
+
+func extraPublicRoutes(r *gin.RouterGroup) {}
+`,
+		"legacy.go": `package router
+
+import "github.com/gin-gonic/gin"
+
+func InitPublicRouter(r *gin.RouterGroup) {}
+
+func InitPrivateRouter(r *gin.RouterGroup) {}
+`,
+		"marked.go": `// Code generated by swag-gin. DO NOT EDIT.
+
+package router
+
+func (s server) extraPublicRoutes(r *gin.RouterGroup) {}
+`,
+		"manual.go": `package router
+
+var engine = gin.New()
+
+func InitPublicRouter(r *gin.RouterGroup) {}
+
+func InitPrivateRouter(r *gin.RouterGroup) {}
+`,
+	}
+	for name, src := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(src), 0666))
+	}
+
+	for name, expected := range map[string]struct {
+		missing   []string
+		generated bool
+	}{
+		"hooked.go": {nil, false},
+		"public.go": {[]string{"extraPrivateRoutes"}, false},
+		"legacy.go": {[]string{"extraPublicRoutes", "extraPrivateRoutes"}, true},
+		"marked.go": {[]string{"extraPublicRoutes", "extraPrivateRoutes"}, true},
+		"manual.go": {[]string{"extraPublicRoutes", "extraPrivateRoutes"}, false},
+	} {
+		missing, generated, err := inspectRouterFile(filepath.Join(dir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, expected.missing, missing, name)
+		assert.Equal(t, expected.generated, generated, name)
+	}
+
+	_, _, err := inspectRouterFile(filepath.Join(dir, "missing.go"))
+	assert.Error(t, err)
+}
+
+func TestGenHookFileKeepsHandWrittenRouter(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	hookPath := filepath.Join(dir, routerHookFile)
+	manual := "package router\n\nfunc extraPublicRoutes(r *gin.RouterGroup) {}\n"
+	require.NoError(t, os.WriteFile(hookPath, []byte(manual), 0666))
+
+	err := genHookFile(Routes{FilePath: dir, PkgName: "router"}, GenConfig{AutoCover: true, Backend: ginBackend{}})
+	assert.EqualError(t, err, hookPath+" is not generated by swag-gin and does not declare extraPrivateRoutes, which router_gen.go calls")
+
+	content, err := os.ReadFile(hookPath)
+	require.NoError(t, err)
+	assert.Equal(t, manual, string(content))
+}
@@ -0,0 +1,129 @@
+package swag
+
+import (
+	"fmt"
+	goparser "go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RouterLayout decides where the router.go of a handler package is generated.
+type RouterLayout interface {
+	// RouterDir returns the directory of the router file for the handlers in handlerDir.
+	RouterDir(handlerDir string) (string, error)
+}
+
+// ParseRouterLayout parses a layout description:
+//
+//	handler                    router.go next to the handlers
+//	parent                     one directory above the handlers, the default
+//	up:N                       N directories above the handlers
+//	dir:PATH                   every router in the single directory PATH
+//	internal/{service}/router  a pattern, where {module} is the name of the handler
+//	                           directory and {service} the name of its parent
+func ParseRouterLayout(layout string) (RouterLayout, error) {
+	switch {
+	case layout == "" || layout == "parent":
+		return upLayout(1), nil
+	case layout == "handler":
+		return upLayout(0), nil
+	case strings.HasPrefix(layout, "up:"):
+		levels, err := strconv.Atoi(strings.TrimPrefix(layout, "up:"))
+		if err != nil || levels < 0 {
+			return nil, fmt.Errorf("invalid router layout %s: up needs a non-negative number of directories", layout)
+		}
+
+		return upLayout(levels), nil
+	case strings.HasPrefix(layout, "dir:"):
+		dir := strings.TrimPrefix(layout, "dir:")
+		if dir == "" {
+			return nil, fmt.Errorf("invalid router layout %s: dir needs a directory", layout)
+		}
+
+		return dirLayout(filepath.Clean(dir)), nil
+	case strings.Contains(layout, "{"):
+		pattern := strings.NewReplacer("{service}", "", "{module}", "").Replace(layout)
+		if strings.ContainsAny(pattern, "{}") {
+			return nil, fmt.Errorf("invalid router layout %s: only {service} and {module} can be used in a pattern", layout)
+		}
+
+		return patternLayout(layout), nil
+	}
+
+	return nil, fmt.Errorf("invalid router layout %s", layout)
+}
+
+// upLayout places the router a number of directories above the handlers.
+type upLayout int
+
+func (l upLayout) RouterDir(handlerDir string) (string, error) {
+	dir := filepath.Clean(handlerDir)
+	for i := 0; i < int(l); i++ {
+		if dir == "." || dir == filepath.Dir(dir) {
+			return "", fmt.Errorf("handler directory %s is not %d directories deep", handlerDir, int(l))
+		}
+
+		dir = filepath.Dir(dir)
+	}
+
+	return dir, nil
+}
+
+// dirLayout places every router in a single directory.
+type dirLayout string
+
+func (l dirLayout) RouterDir(string) (string, error) {
+	return string(l), nil
+}
+
+// patternLayout places the router in a directory built from the handler location.
+type patternLayout string
+
+func (l patternLayout) RouterDir(handlerDir string) (string, error) {
+	module := filepath.Clean(handlerDir)
+	service := filepath.Dir(module)
+	if module == "." || service == "." || service == module {
+		return "", fmt.Errorf("handler directory %s has no parent to use as {service} in %s", handlerDir, string(l))
+	}
+
+	return filepath.Clean(strings.NewReplacer(
+		"{service}", filepath.Base(service),
+		"{module}", filepath.Base(module),
+	).Replace(string(l))), nil
+}
+
+// routerPackageName returns the package name of the router generated in dir:
+// the package already declared there, or otherwise the directory name.
+func routerPackageName(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+
+	for _, file := range files {
+		if filepath.Base(file) == routerHookFile || filepath.Base(file) == routerGenFile || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		astFile, err := goparser.ParseFile(token.NewFileSet(), file, nil, goparser.PackageClauseOnly)
+		if err != nil {
+			return "", err
+		}
+
+		return astFile.Name.Name, nil
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	pkgName := strings.NewReplacer("-", "_", ".", "_").Replace(filepath.Base(absDir))
+	if !token.IsIdentifier(pkgName) {
+		return "", fmt.Errorf("can not derive a package name for router directory %s", dir)
+	}
+
+	return pkgName, nil
+}
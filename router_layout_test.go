@@ -0,0 +1,76 @@
+package swag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRouterLayout(t *testing.T) {
+	t.Parallel()
+
+	handlerDir := filepath.Join("service", "user", "api")
+
+	tests := []struct {
+		layout   string
+		expected string
+	}{
+		{"", filepath.Join("service", "user")},
+		{"parent", filepath.Join("service", "user")},
+		{"handler", handlerDir},
+		{"up:2", "service"},
+		{"dir:internal/router", filepath.Join("internal", "router")},
+		{"internal/{service}/router", filepath.Join("internal", "user", "router")},
+		{"routers/{service}_{module}", filepath.Join("routers", "user_api")},
+	}
+	for _, test := range tests {
+		layout, err := ParseRouterLayout(test.layout)
+		require.NoError(t, err, test.layout)
+
+		dir, err := layout.RouterDir(handlerDir)
+		assert.NoError(t, err, test.layout)
+		assert.Equal(t, test.expected, dir, test.layout)
+	}
+
+	for _, invalid := range []string{"up:x", "up:-1", "dir:", "internal/{pkg}/router", "nowhere"} {
+		_, err := ParseRouterLayout(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRouterLayoutTooShallow(t *testing.T) {
+	t.Parallel()
+
+	layout, err := ParseRouterLayout("up:3")
+	require.NoError(t, err)
+	_, err = layout.RouterDir(filepath.Join("user", "api"))
+	assert.Error(t, err)
+
+	layout, err = ParseRouterLayout("internal/{service}/router")
+	require.NoError(t, err)
+	_, err = layout.RouterDir("api")
+	assert.Error(t, err)
+}
+
+func TestRouterPackageName(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "user-service")
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+
+	pkgName, err := routerPackageName(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "user_service", pkgName)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "router.go"), []byte("package stale\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user.go"), []byte("package user\n"), 0644))
+	pkgName, err = routerPackageName(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "user", pkgName)
+
+	_, err = routerPackageName(filepath.Join(t.TempDir(), "1st"))
+	assert.Error(t, err)
+}